@@ -17,33 +17,128 @@
 package electron
 
 import (
+	"fmt"
+
 	"github.com/gopherjs/gopherjs/js"
 )
 
 var (
-	require   = js.Global.Get("require")
-	electron  = require.Invoke("electron")
-	remote    = electron.Get("remote")
-	useRemote = false
+	require  = js.Global.Get("require")
+	electron = require.Invoke("electron")
+	remote   = electron.Get("remote")
+	// rendererProcess is detected from `process.type` at init
+	rendererProcess = processType() == ProcessTypeRenderer
 )
 
 //go:generate -command json2rawApi go run json2rawApi/main.go json2rawApi/types.go json2rawApi/templates.go
 //go:generate json2rawApi -c -o . json2rawApi/electron-api-1.4.15.json
 
+// process types as reported by `process.type`
+const (
+	ProcessTypeBrowser  = "browser"
+	ProcessTypeRenderer = "renderer"
+)
+
+// processType returns `process.type`, "browser" for the main process and
+// "renderer" for web pages
+func processType() string {
+	t := js.Global.Get("process").Get("type")
+	if isNullish(t) {
+		return ""
+	}
+	return t.String()
+}
+
+// IsMainProcess reports whether the code runs in the electron main process
+func IsMainProcess() bool {
+	return !rendererProcess
+}
+
+// modules only available in the main process, see electron docs
+var mainModules = map[string]bool{
+	"app":               true,
+	"autoUpdater":       true,
+	"BrowserWindow":     true,
+	"contentTracing":    true,
+	"dialog":            true,
+	"globalShortcut":    true,
+	"ipcMain":           true,
+	"Menu":              true,
+	"MenuItem":          true,
+	"net":               true,
+	"powerMonitor":      true,
+	"powerSaveBlocker":  true,
+	"protocol":          true,
+	"session":           true,
+	"systemPreferences": true,
+	"Tray":              true,
+	"webContents":       true,
+}
+
+// modules only available in renderer processes
+var rendererModules = map[string]bool{
+	"desktopCapturer": true,
+	"ipcRenderer":     true,
+	"remote":          true,
+	"webFrame":        true,
+}
+
+// ModuleRoute tells GetModuleEx how to reach a module
+type ModuleRoute int
+
+// Module routes
+const (
+	// RouteAuto gets main process only modules through `electron.remote` in
+	// renderer processes and every other module directly
+	RouteAuto ModuleRoute = iota
+	// RouteDirect always uses `electron.<name>`
+	RouteDirect
+	// RouteRemote always uses `electron.remote.<name>`
+	RouteRemote
+)
+
 func GetApp() *AppModule {
 	return GetAppModule()
 }
 
-// Get returns a electron or `electron.remote` module
-func Get(name string) *js.Object {
-	if useRemote {
-		return remote.Get(name)
+// GetModuleEx returns the electron module name reached through route, or
+// an error when the module is not available that way in the current process.
+func GetModuleEx(name string, route ModuleRoute) (*js.Object, error) {
+	if route == RouteAuto {
+		route = RouteDirect
+		if rendererProcess && mainModules[name] {
+			route = RouteRemote
+		}
 	}
-	return electron.Get(name)
+	switch {
+	case !rendererProcess && rendererModules[name]:
+		return nil, fmt.Errorf("electron: module %q is only available in renderer processes", name)
+	case rendererProcess && route == RouteDirect && mainModules[name]:
+		return nil, fmt.Errorf("electron: module %q is only available in the main process, use RouteRemote", name)
+	case route == RouteRemote && rendererModules[name]:
+		return nil, fmt.Errorf("electron: module %q can not be reached through electron.remote", name)
+	case route == RouteRemote && isNullish(remote):
+		return nil, fmt.Errorf("electron: module %q needs electron.remote which is not available in this process", name)
+	case route == RouteRemote:
+		return remote.Get(name), nil
+	}
+	return electron.Get(name), nil
 }
 
-// UseRemote switch `electron.Get` to get module through `electron.remote`
-// this functon must be call when use electron.remote in render process
-func UseRemote() {
-	useRemote = true
+// Get returns a electron or `electron.remote` module, main process only
+// modules are got through `electron.remote` in renderer processes.
+// It returns undefined when the module can not be reached, use GetModuleEx
+// to get the reason.
+func Get(name string) *js.Object {
+	o, err := GetModuleEx(name, RouteAuto)
+	if err != nil {
+		return electron.Get(name)
+	}
+	return o
 }
+
+// UseRemote used to switch `electron.Get` to get module through `electron.remote`.
+//
+// Deprecated: the process type is detected at init and main process only
+// modules are got through `electron.remote` automatically, this function does nothing.
+func UseRemote() {}
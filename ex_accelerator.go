@@ -0,0 +1,329 @@
+package electron
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// Accelerator is a keyboard shortcut as described by
+// https://electron.atom.io/docs/api/accelerator/
+// The zero value is no accelerator.
+type Accelerator struct {
+	CommandOrControl bool // Command on macOS, Control elsewhere
+	Command          bool
+	Control          bool
+	Alt              bool // also Option
+	AltGr            bool
+	Shift            bool
+	Super            bool
+	Key              string // canonical key code, e.g. "K", "F5", "Plus", "Up"
+}
+
+// acceleratorKeys maps the lower cased key codes and aliases electron
+// accepts to their canonical form, letters, digits and F1-F24 are added
+// by init
+var acceleratorKeys = map[string]string{
+	"plus": "Plus", "space": "Space", "tab": "Tab", "backspace": "Backspace",
+	"delete": "Delete", "insert": "Insert", "return": "Return", "enter": "Return",
+	"up": "Up", "down": "Down", "left": "Left", "right": "Right",
+	"home": "Home", "end": "End", "pageup": "PageUp", "pagedown": "PageDown",
+	"escape": "Escape", "esc": "Escape",
+	"volumeup": "VolumeUp", "volumedown": "VolumeDown", "volumemute": "VolumeMute",
+	"medianexttrack": "MediaNextTrack", "mediaprevioustrack": "MediaPreviousTrack",
+	"mediastop": "MediaStop", "mediaplaypause": "MediaPlayPause",
+	"printscreen": "PrintScreen",
+}
+
+// punctuation keys accepted as is
+const acceleratorPunctuation = ")!@#$%^&*(:;<=>?,-_.~`/|\\[]{}'\""
+
+func init() {
+	for c := 'A'; c <= 'Z'; c++ {
+		acceleratorKeys[strings.ToLower(string(c))] = string(c)
+	}
+	for c := '0'; c <= '9'; c++ {
+		acceleratorKeys[string(c)] = string(c)
+	}
+	for i := 1; i <= 24; i++ {
+		f := fmt.Sprintf("F%d", i)
+		acceleratorKeys[strings.ToLower(f)] = f
+	}
+	for _, c := range acceleratorPunctuation {
+		acceleratorKeys[string(c)] = string(c)
+	}
+}
+
+// ParseAccelerator parses accelerators like "CmdOrCtrl+Shift+K", modifiers
+// and key codes are case insensitive and validated against electron's
+// documented set.
+func ParseAccelerator(s string) (Accelerator, error) {
+	a := Accelerator{}
+	if strings.TrimSpace(s) == "" {
+		return a, errors.New("electron: empty accelerator")
+	}
+	parts := strings.Split(s, "+")
+	for i, p := range parts {
+		p = strings.TrimSpace(p)
+		last := i == len(parts)-1
+		if p == "" {
+			return Accelerator{}, fmt.Errorf("electron: invalid accelerator %q, use Plus for the + key", s)
+		}
+		if !last {
+			if err := a.setModifier(p); err != nil {
+				return Accelerator{}, fmt.Errorf("electron: invalid accelerator %q: %v", s, err)
+			}
+			continue
+		}
+		key, ok := acceleratorKeys[strings.ToLower(p)]
+		if !ok {
+			return Accelerator{}, fmt.Errorf("electron: invalid accelerator %q: unknown key %q", s, p)
+		}
+		a.Key = key
+	}
+	return a, nil
+}
+
+// MustParseAccelerator is ParseAccelerator panicking on errors, for
+// accelerators written in the code
+func MustParseAccelerator(s string) Accelerator {
+	a, err := ParseAccelerator(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+func (a *Accelerator) setModifier(m string) error {
+	switch strings.ToLower(m) {
+	case "commandorcontrol", "cmdorctrl":
+		a.CommandOrControl = true
+	case "command", "cmd":
+		a.Command = true
+	case "control", "ctrl":
+		a.Control = true
+	case "alt", "option":
+		a.Alt = true
+	case "altgr":
+		a.AltGr = true
+	case "shift":
+		a.Shift = true
+	case "super":
+		a.Super = true
+	default:
+		return fmt.Errorf("unknown modifier %q", m)
+	}
+	return nil
+}
+
+// IsZero reports whether a is the empty accelerator
+func (a Accelerator) IsZero() bool {
+	return a == Accelerator{}
+}
+
+// String returns the canonical electron form, e.g. "CommandOrControl+Shift+K"
+func (a Accelerator) String() string {
+	if a.IsZero() {
+		return ""
+	}
+	parts := []string{}
+	add := func(on bool, name string) {
+		if on {
+			parts = append(parts, name)
+		}
+	}
+	add(a.CommandOrControl, "CommandOrControl")
+	add(a.Command, "Command")
+	add(a.Control, "Control")
+	add(a.Alt, "Alt")
+	add(a.AltGr, "AltGr")
+	add(a.Shift, "Shift")
+	add(a.Super, "Super")
+	return strings.Join(append(parts, a.Key), "+")
+}
+
+// normalize resolves CommandOrControl for platform
+func (a Accelerator) normalize(platform string) Accelerator {
+	if a.CommandOrControl {
+		a.CommandOrControl = false
+		if platform == "darwin" {
+			a.Command = true
+		} else {
+			a.Control = true
+		}
+	}
+	return a
+}
+
+var macKeySymbols = map[string]string{
+	"Plus": "+", "Space": "Space", "Tab": "⇥", "Backspace": "⌫", "Delete": "⌦",
+	"Return": "↩", "Up": "↑", "Down": "↓", "Left": "←", "Right": "→",
+	"Home": "↖", "End": "↘", "PageUp": "⇞", "PageDown": "⇟", "Escape": "⎋",
+}
+
+// Display returns the text shown for a on platform (as `process.platform`,
+// the running one when empty): "⌃⌥⇧⌘K" on macOS, "Ctrl+Alt+Shift+K" elsewhere.
+func (a Accelerator) Display(platform string) string {
+	if platform == "" {
+		platform = js.Global.Get("process").Get("platform").String()
+	}
+	a = a.normalize(platform)
+	if platform == "darwin" {
+		s := ""
+		if a.Control {
+			s += "⌃"
+		}
+		if a.Alt || a.AltGr {
+			s += "⌥"
+		}
+		if a.Shift {
+			s += "⇧"
+		}
+		if a.Command || a.Super {
+			s += "⌘"
+		}
+		if sym, ok := macKeySymbols[a.Key]; ok {
+			return s + sym
+		}
+		return s + a.Key
+	}
+	parts := []string{}
+	if a.Control {
+		parts = append(parts, "Ctrl")
+	}
+	if a.Alt {
+		parts = append(parts, "Alt")
+	}
+	if a.AltGr {
+		parts = append(parts, "AltGr")
+	}
+	if a.Shift {
+		parts = append(parts, "Shift")
+	}
+	// Command is the Super key off macOS, as in MatchInput
+	if a.Super || a.Command {
+		if platform == "win32" {
+			parts = append(parts, "Win")
+		} else {
+			parts = append(parts, "Super")
+		}
+	}
+	key := a.Key
+	if key == "Plus" {
+		key = "+"
+	}
+	return strings.Join(append(parts, key), "+")
+}
+
+// dom `KeyboardEvent.key` values of the named key codes
+var acceleratorDomKeys = map[string]string{
+	"Plus": "+", "Space": " ", "Tab": "Tab", "Backspace": "Backspace",
+	"Delete": "Delete", "Insert": "Insert", "Return": "Enter",
+	"Up": "ArrowUp", "Down": "ArrowDown", "Left": "ArrowLeft", "Right": "ArrowRight",
+	"Home": "Home", "End": "End", "PageUp": "PageUp", "PageDown": "PageDown",
+	"Escape": "Escape", "PrintScreen": "PrintScreen",
+	"VolumeUp": "AudioVolumeUp", "VolumeDown": "AudioVolumeDown", "VolumeMute": "AudioVolumeMute",
+	"MediaNextTrack": "MediaTrackNext", "MediaPreviousTrack": "MediaTrackPrevious",
+	"MediaStop": "MediaStop", "MediaPlayPause": "MediaPlayPause",
+}
+
+// MatchInput reports whether the `input` object of the WebContents
+// `before-input-event` event is a key down of a
+func (a Accelerator) MatchInput(input *js.Object) bool {
+	if isNullish(input) {
+		return false
+	}
+	in := keyInput{
+		Type:    input.Get("type").String(),
+		Key:     input.Get("key").String(),
+		Code:    jsString(input.Get("code")),
+		Control: input.Get("control").Bool(),
+		Alt:     input.Get("alt").Bool(),
+		Shift:   input.Get("shift").Bool(),
+		Meta:    input.Get("meta").Bool(),
+	}
+	return a.matchInput(in, js.Global.Get("process").Get("platform").String())
+}
+
+// keyInput is the `input` of the `before-input-event` event
+type keyInput struct {
+	Type, Key, Code           string
+	Control, Alt, Shift, Meta bool
+}
+
+func (a Accelerator) matchInput(in keyInput, platform string) bool {
+	if a.IsZero() || in.Type != "keyDown" {
+		return false
+	}
+	n := a.normalize(platform)
+	if in.Control != n.Control ||
+		in.Alt != (n.Alt || n.AltGr) ||
+		in.Meta != (n.Command || n.Super) {
+		return false
+	}
+	// the symbols typed with shift, like + or !, carry it in their key
+	symbol := n.Key == "Plus" || len(n.Key) == 1 && strings.Contains(acceleratorPunctuation, n.Key)
+	if in.Shift != n.Shift && !(symbol && in.Shift) {
+		return false
+	}
+	if dom, ok := acceleratorDomKeys[n.Key]; ok {
+		return in.Key == dom
+	}
+	if strings.EqualFold(in.Key, n.Key) {
+		return true
+	}
+	if len(n.Key) != 1 || symbol || isASCIIAlnum(in.Key) {
+		return false
+	}
+	// letters and digits changed by shift or alt, like ! for Shift+1 or ˚
+	// for Option+K, are matched by their physical key
+	if c := n.Key[0]; c >= 'A' && c <= 'Z' {
+		return in.Code == "Key"+n.Key
+	}
+	return in.Code == "Digit"+n.Key
+}
+
+func isASCIIAlnum(s string) bool {
+	if len(s) != 1 {
+		return false
+	}
+	c := s[0]
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// HandleAcceleratorEx calls fn and prevents the default handling when a is
+// pressed in wc, through the `before-input-event` event
+func HandleAcceleratorEx(wc *WebContents, a Accelerator, fn func()) {
+	wc.On(EvtWebContentsBeforeInputEvent, func(args ...*js.Object) {
+		// args: event, input
+		if len(args) > 1 && a.MatchInput(args[1]) {
+			args[0].Call("preventDefault")
+			fn()
+		}
+	})
+}
+
+// RegisterEx registers a as a global shortcut calling fn, an error is
+// returned when the accelerator is taken by another application
+func (g *GlobalShortcutModule) RegisterEx(a Accelerator, fn func()) error {
+	if a.IsZero() {
+		return errors.New("electron: empty accelerator")
+	}
+	g.Call("register", a.String(), fn)
+	if !g.Call("isRegistered", a.String()).Bool() {
+		return fmt.Errorf("electron: global shortcut %s could not be registered", a)
+	}
+	return nil
+}
+
+// UnregisterEx unregisters the global shortcut a
+func (g *GlobalShortcutModule) UnregisterEx(a Accelerator) {
+	g.Call("unregister", a.String())
+}
+
+// IsRegisteredEx reports whether this application registered a
+func (g *GlobalShortcutModule) IsRegisteredEx(a Accelerator) bool {
+	return g.Call("isRegistered", a.String()).Bool()
+}
@@ -0,0 +1,96 @@
+package electron
+
+import "testing"
+
+func TestParseAccelerator(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string // canonical form, empty for an error
+	}{
+		{"CmdOrCtrl+Shift+K", "CommandOrControl+Shift+K"},
+		{"commandorcontrol+k", "CommandOrControl+K"},
+		{"Ctrl+Alt+Delete", "Control+Alt+Delete"},
+		{"Option+Cmd+Esc", "Command+Alt+Escape"},
+		{"CmdOrCtrl+Plus", "CommandOrControl+Plus"},
+		{"Super+f12", "Super+F12"},
+		{"AltGr+enter", "AltGr+Return"},
+		{"Shift+/", "Shift+/"},
+		{" Ctrl + A ", "Control+A"},
+		{"F24", "F24"},
+		{"", ""},
+		{"Ctrl+", ""},
+		{"Ctrl++", ""},
+		{"Hyper+K", ""},
+		{"Ctrl+F25", ""},
+		{"Ctrl+KK", ""},
+	}
+	for _, tt := range tests {
+		a, err := ParseAccelerator(tt.in)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("ParseAccelerator(%q) = %v, want an error", tt.in, a)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseAccelerator(%q): %v", tt.in, err)
+			continue
+		}
+		if got := a.String(); got != tt.want {
+			t.Errorf("ParseAccelerator(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestAcceleratorDisplay(t *testing.T) {
+	tests := []struct {
+		in, platform, want string
+	}{
+		{"CmdOrCtrl+Shift+K", "darwin", "⇧⌘K"},
+		{"CmdOrCtrl+Shift+K", "linux", "Ctrl+Shift+K"},
+		{"Ctrl+Alt+Up", "darwin", "⌃⌥↑"},
+		{"CmdOrCtrl+Plus", "win32", "Ctrl++"},
+		{"CmdOrCtrl+Plus", "darwin", "⌘+"},
+		{"Command+Q", "win32", "Win+Q"},
+		{"Super+Q", "linux", "Super+Q"},
+		{"Super+Q", "darwin", "⌘Q"},
+	}
+	for _, tt := range tests {
+		if got := MustParseAccelerator(tt.in).Display(tt.platform); got != tt.want {
+			t.Errorf("%s on %s displays %q, want %q", tt.in, tt.platform, got, tt.want)
+		}
+	}
+}
+
+func TestAcceleratorMatchInput(t *testing.T) {
+	tests := []struct {
+		accel, platform string
+		in              keyInput
+		want            bool
+	}{
+		{"CmdOrCtrl+K", "linux", keyInput{Type: "keyDown", Key: "k", Code: "KeyK", Control: true}, true},
+		{"CmdOrCtrl+K", "darwin", keyInput{Type: "keyDown", Key: "k", Code: "KeyK", Meta: true}, true},
+		{"CmdOrCtrl+K", "darwin", keyInput{Type: "keyDown", Key: "k", Code: "KeyK", Control: true}, false},
+		{"CmdOrCtrl+K", "linux", keyInput{Type: "keyUp", Key: "k", Code: "KeyK", Control: true}, false},
+		{"CmdOrCtrl+K", "linux", keyInput{Type: "keyDown", Key: "K", Code: "KeyK", Control: true, Shift: true}, false},
+		{"CmdOrCtrl+Shift+K", "linux", keyInput{Type: "keyDown", Key: "K", Code: "KeyK", Control: true, Shift: true}, true},
+		{"CmdOrCtrl+Plus", "linux", keyInput{Type: "keyDown", Key: "+", Code: "Equal", Control: true, Shift: true}, true},
+		{"CmdOrCtrl+Plus", "linux", keyInput{Type: "keyDown", Key: "+", Code: "NumpadAdd", Control: true}, true},
+		{"CmdOrCtrl+Plus", "linux", keyInput{Type: "keyDown", Key: "=", Code: "Equal", Control: true}, false},
+		{"Ctrl+!", "win32", keyInput{Type: "keyDown", Key: "!", Code: "Digit1", Control: true, Shift: true}, true},
+		{"Ctrl+Shift+1", "win32", keyInput{Type: "keyDown", Key: "!", Code: "Digit1", Control: true, Shift: true}, true},
+		{"Ctrl+1", "win32", keyInput{Type: "keyDown", Key: "!", Code: "Digit1", Control: true, Shift: true}, false},
+		{"Alt+K", "darwin", keyInput{Type: "keyDown", Key: "˚", Code: "KeyK", Alt: true}, true},
+		{"Ctrl+Q", "linux", keyInput{Type: "keyDown", Key: "a", Code: "KeyQ", Control: true}, false},
+		{"Ctrl+A", "linux", keyInput{Type: "keyDown", Key: "a", Code: "KeyQ", Control: true}, true},
+		{"Escape", "linux", keyInput{Type: "keyDown", Key: "Escape", Code: "Escape"}, true},
+		{"Command+Up", "linux", keyInput{Type: "keyDown", Key: "ArrowUp", Code: "ArrowUp", Meta: true}, true},
+		{"Super+Space", "win32", keyInput{Type: "keyDown", Key: " ", Code: "Space", Meta: true}, true},
+		{"AltGr+E", "linux", keyInput{Type: "keyDown", Key: "€", Code: "KeyE", Alt: true}, true},
+	}
+	for _, tt := range tests {
+		if got := MustParseAccelerator(tt.accel).matchInput(tt.in, tt.platform); got != tt.want {
+			t.Errorf("%s on %s matching %+v = %v, want %v", tt.accel, tt.platform, tt.in, got, tt.want)
+		}
+	}
+}
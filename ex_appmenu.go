@@ -0,0 +1,242 @@
+package electron
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// ids of the menus created by NewStandardMenuEx, items with a role use the
+// role as id, e.g. "edit" contains "undo", "redo", "cut", ...
+const (
+	MenuIDApp    = "app" // macOS only
+	MenuIDFile   = "file"
+	MenuIDEdit   = "edit"
+	MenuIDView   = "view"
+	MenuIDWindow = "window"
+	MenuIDHelp   = "help"
+)
+
+// StandardMenuOptions configures NewStandardMenuEx
+type StandardMenuOptions struct {
+	// AppName is the label of the macOS application menu, default to app.getName()
+	AppName string
+	// HelpURL adds a "Learn More" item to the help menu opening it in the default browser
+	HelpURL string
+	// Extra menus, added between the "View" and "Window" menus
+	Extra []MenuItemOptionEx
+	// Platform as `process.platform`, default to the running platform
+	Platform string
+}
+
+// StandardMenu is the default application menu of a platform, custom items
+// can be inserted before building it.
+type StandardMenu struct {
+	template []MenuItemOptionEx
+}
+
+func roleItem(role string) MenuItemOptionEx {
+	return MenuItemOptionEx{ID: role, Role: role}
+}
+
+func separatorItem() MenuItemOptionEx {
+	return MenuItemOptionEx{Type: TypeSeparator}
+}
+
+// NewStandardMenuEx creates the platform appropriate default application
+// menu: App (macOS), File (Windows and Linux), Edit, View, Window and Help.
+func NewStandardMenuEx(opt StandardMenuOptions) *StandardMenu {
+	platform := opt.Platform
+	if platform == "" {
+		platform = js.Global.Get("process").Get("platform").String()
+	}
+	mac := platform == "darwin"
+	m := &StandardMenu{}
+	if mac {
+		name := opt.AppName
+		if name == "" {
+			name = GetApp().GetName()
+		}
+		m.template = append(m.template, MenuItemOptionEx{
+			ID:    MenuIDApp,
+			Label: name,
+			SubMenuOptions: []MenuItemOptionEx{
+				roleItem(RoleAbout),
+				separatorItem(),
+				{ID: RoleServices, Role: RoleServices, SubMenuOptions: []MenuItemOptionEx{}},
+				separatorItem(),
+				roleItem(RoleHide),
+				roleItem(RoleHideothers),
+				roleItem(RoleUnhide),
+				separatorItem(),
+				roleItem(RoleQuit),
+			},
+		})
+	} else {
+		m.template = append(m.template, MenuItemOptionEx{
+			ID:    MenuIDFile,
+			Label: "&File",
+			SubMenuOptions: []MenuItemOptionEx{
+				roleItem(RoleQuit),
+			},
+		})
+	}
+	edit := []MenuItemOptionEx{
+		roleItem(RoleUndo),
+		roleItem(RoleRedo),
+		separatorItem(),
+		roleItem(RoleCut),
+		roleItem(RoleCopy),
+		roleItem(RolePaste),
+		roleItem(RolePasteandmatchstyle),
+		roleItem(RoleDelete),
+		roleItem(RoleSelectall),
+	}
+	if mac {
+		edit = append(edit,
+			separatorItem(),
+			MenuItemOptionEx{
+				ID:    "speech",
+				Label: "Speech",
+				SubMenuOptions: []MenuItemOptionEx{
+					roleItem(RoleStartspeaking),
+					roleItem(RoleStopspeaking),
+				},
+			})
+	}
+	m.template = append(m.template,
+		MenuItemOptionEx{ID: MenuIDEdit, Label: "&Edit", SubMenuOptions: edit},
+		MenuItemOptionEx{ID: MenuIDView, Label: "&View", SubMenuOptions: []MenuItemOptionEx{
+			roleItem(RoleReload),
+			roleItem(RoleToggledevtools),
+			separatorItem(),
+			roleItem(RoleResetzoom),
+			roleItem(RoleZoomin),
+			roleItem(RoleZoomout),
+			separatorItem(),
+			roleItem(RoleTogglefullscreen),
+		}},
+	)
+	m.template = append(m.template, opt.Extra...)
+	window := []MenuItemOptionEx{
+		roleItem(RoleMinimize),
+		roleItem(RoleClose),
+	}
+	if mac {
+		window = append(window,
+			roleItem(RoleZoom),
+			separatorItem(),
+			roleItem(RoleFront),
+		)
+	}
+	help := []MenuItemOptionEx{}
+	if opt.HelpURL != "" {
+		url := opt.HelpURL
+		help = append(help, MenuItemOptionEx{
+			ID:    "learn-more",
+			Label: "Learn More",
+			Click: func() {
+				Get("shell").Call("openExternal", url)
+			},
+		})
+	}
+	m.template = append(m.template,
+		MenuItemOptionEx{ID: MenuIDWindow, Label: "&Window", Role: RoleWindow, SubMenuOptions: window},
+		MenuItemOptionEx{ID: MenuIDHelp, Label: "&Help", Role: RoleHelp, SubMenuOptions: help},
+	)
+	return m
+}
+
+// findMenuItems returns the items of the submenu with id, the menu bar for ""
+func findMenuItems(items *[]MenuItemOptionEx, id string) *[]MenuItemOptionEx {
+	if id == "" {
+		return items
+	}
+	for i := range *items {
+		item := &(*items)[i]
+		if item.ID == id && item.SubMenuOptions != nil {
+			return &item.SubMenuOptions
+		}
+		if found := findMenuItems(&item.SubMenuOptions, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// Insert adds item to the menu with id parent, "" being the menu bar.
+// item.Position places it relative to another item of the same menu with
+// "before=<id>", "after=<id>" or "endof=<id>", it is appended when empty.
+func (m *StandardMenu) Insert(parent string, item MenuItemOptionEx) error {
+	items := findMenuItems(&m.template, parent)
+	if items == nil {
+		return fmt.Errorf("electron: no menu with id %q", parent)
+	}
+	if err := checkPosition(*items, item.Position); err != nil {
+		return err
+	}
+	*items = append(*items, item)
+	return nil
+}
+
+// Template returns the menu template, to be used with BuildFromTemplateEx
+func (m *StandardMenu) Template() []MenuItemOptionEx {
+	return m.template
+}
+
+// Build validates the template and builds the native Menu
+func (m *StandardMenu) Build() (*Menu, error) {
+	if err := ValidateMenuTemplateEx(m.template); err != nil {
+		return nil, err
+	}
+	return BuildFromTemplateEx(m.template), nil
+}
+
+func checkPosition(items []MenuItemOptionEx, position string) error {
+	if position == "" {
+		return nil
+	}
+	parts := strings.SplitN(position, "=", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("electron: invalid menu item position %q", position)
+	}
+	switch parts[0] {
+	case "before", "after", "endof":
+	default:
+		return fmt.Errorf("electron: invalid menu item position %q", position)
+	}
+	for _, item := range items {
+		if item.ID == parts[1] {
+			return nil
+		}
+	}
+	return fmt.Errorf("electron: menu item position %q refers to an unknown id", position)
+}
+
+// ValidateMenuTemplateEx checks the roles, types, ids and positions of a
+// menu template and its submenus
+func ValidateMenuTemplateEx(items []MenuItemOptionEx) error {
+	ids := make(map[string]bool)
+	for _, item := range items {
+		if item.Role != "" && !IsValidRole(item.Role) {
+			return fmt.Errorf("electron: invalid menu item role %q", item.Role)
+		}
+		if item.Type != "" && !IsValidType(item.Type) {
+			return fmt.Errorf("electron: invalid menu item type %q", item.Type)
+		}
+		if item.ID != "" {
+			if ids[item.ID] {
+				return fmt.Errorf("electron: duplicated menu item id %q", item.ID)
+			}
+			ids[item.ID] = true
+		}
+		if err := checkPosition(items, item.Position); err != nil {
+			return err
+		}
+		if err := ValidateMenuTemplateEx(item.SubMenuOptions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
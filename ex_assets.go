@@ -0,0 +1,204 @@
+package electron
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// mime types of the usual web assets, the builtin table of the mime package
+// misses some of them depending on the go version
+var assetMimeTypes = map[string]string{
+	".css":   "text/css; charset=utf-8",
+	".html":  "text/html; charset=utf-8",
+	".htm":   "text/html; charset=utf-8",
+	".js":    "application/javascript; charset=utf-8",
+	".mjs":   "application/javascript; charset=utf-8",
+	".json":  "application/json; charset=utf-8",
+	".map":   "application/json; charset=utf-8",
+	".txt":   "text/plain; charset=utf-8",
+	".svg":   "image/svg+xml",
+	".ico":   "image/x-icon",
+	".wasm":  "application/wasm",
+	".woff":  "font/woff",
+	".woff2": "font/woff2",
+	".ttf":   "font/ttf",
+	".otf":   "font/otf",
+}
+
+// AssetOptions tunes an AssetServer
+type AssetOptions struct {
+	// Index is the file served for directories, default to index.html
+	Index string
+	// Fallback is served for the missing paths without extension, e.g.
+	// "/index.html" for single page applications using client side routing.
+	// Missing files are 404 when empty.
+	Fallback string
+}
+
+// AssetServer is an http.Handler serving the files of an http.FileSystem,
+// typically an asset tree bundled into the binary. It detects mime types,
+// serves index files, supports the fallback of single page applications,
+// and sets ETag and handles Range requests through http.ServeContent.
+type AssetServer struct {
+	fs    http.FileSystem
+	opt   AssetOptions
+	mu    sync.Mutex
+	etags map[string]assetETag
+}
+
+type assetETag struct {
+	size    int64
+	modTime time.Time
+	etag    string
+}
+
+// NewAssetServerEx creates an AssetServer serving fs, opt can be nil
+func NewAssetServerEx(fs http.FileSystem, opt *AssetOptions) *AssetServer {
+	s := &AssetServer{
+		fs:    fs,
+		etags: make(map[string]assetETag),
+	}
+	if opt != nil {
+		s.opt = *opt
+	}
+	if s.opt.Index == "" {
+		s.opt.Index = "index.html"
+	}
+	return s
+}
+
+// open returns the file for the cleaned path name, following index files
+func (s *AssetServer) open(name string) (http.File, os.FileInfo, string, error) {
+	f, err := s.fs.Open(name)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, "", err
+	}
+	if fi.IsDir() {
+		f.Close()
+		return s.openFile(path.Join(name, s.opt.Index))
+	}
+	return f, fi, name, nil
+}
+
+func (s *AssetServer) openFile(name string) (http.File, os.FileInfo, string, error) {
+	f, err := s.fs.Open(name)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	fi, err := f.Stat()
+	if err == nil && fi.IsDir() {
+		err = os.ErrNotExist
+	}
+	if err != nil {
+		f.Close()
+		return nil, nil, "", err
+	}
+	return f, fi, name, nil
+}
+
+// etag returns the strong ETag of f, cached while its size and modification
+// time do not change
+func (s *AssetServer) etag(name string, f http.File, fi os.FileInfo) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.etags[name]; ok && e.size == fi.Size() && e.modTime.Equal(fi.ModTime()) {
+		return e.etag, nil
+	}
+	h := sha1.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	etag := `"` + hex.EncodeToString(h.Sum(nil)) + `"`
+	s.etags[name] = assetETag{size: fi.Size(), modTime: fi.ModTime(), etag: etag}
+	return etag, nil
+}
+
+func (s *AssetServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" && r.Method != "HEAD" {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "405 method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	upath := path.Clean("/" + r.URL.Path)
+	f, fi, name, err := s.open(upath)
+	if os.IsNotExist(err) && s.opt.Fallback != "" && path.Ext(upath) == "" {
+		f, fi, name, err = s.openFile(path.Clean("/" + s.opt.Fallback))
+	}
+	if err != nil {
+		if os.IsNotExist(err) {
+			http.NotFound(w, r)
+		} else {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	defer f.Close()
+	etag, err := s.etag(name, f, fi)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	ext := strings.ToLower(path.Ext(name))
+	if ct, ok := assetMimeTypes[ext]; ok {
+		w.Header().Set("Content-Type", ct)
+	} else if ct := mime.TypeByExtension(ext); ct != "" {
+		w.Header().Set("Content-Type", ct)
+	}
+	// ServeContent sniffs the content when there is no Content-Type
+	http.ServeContent(w, r, name, fi.ModTime(), f)
+}
+
+// RegisterAssetSchemeEx registers scheme as a standard and secure scheme,
+// so that relative urls, cookies, localStorage and fetch work as with https.
+// In the main process it must be called before the app is ready, in renderer
+// processes (e.g. in a preload script) it registers the scheme as privileged.
+func RegisterAssetSchemeEx(scheme string) error {
+	if rendererProcess {
+		wf, err := GetModuleEx("webFrame", RouteAuto)
+		if err != nil {
+			return err
+		}
+		return jsTry(func() {
+			wf.Call("registerURLSchemeAsPrivileged", scheme)
+		})
+	}
+	return jsTry(func() {
+		GetProtocolModule().Call("registerStandardSchemes", []string{scheme}, map[string]interface{}{"secure": true})
+	})
+}
+
+// ServeAssetsEx serves fs on scheme, registered with RegisterAssetSchemeEx,
+// e.g. `bw.LoadURL("app://ui/index.html", nil)`. Like ServeHandler it must
+// be called after the app is ready, from a goroutine.
+//
+// Range requests are served whole when electron has no
+// `protocol.registerStreamProtocol`, as partial responses need their status.
+func ServeAssetsEx(scheme string, fs http.FileSystem, opt *AssetOptions) error {
+	p := GetProtocolModule()
+	var h http.Handler = NewAssetServerEx(fs, opt)
+	if isNullish(p.Get("registerStreamProtocol")) {
+		next := h
+		h = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.Header.Del("Range")
+			next.ServeHTTP(w, r)
+		})
+	}
+	return p.ServeHandler(scheme, h)
+}
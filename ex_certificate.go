@@ -0,0 +1,160 @@
+package electron
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"time"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// CertificatePrincipalEx is the go form of CertificatePrincipal
+type CertificatePrincipalEx struct {
+	CommonName        string
+	Organizations     []string
+	OrganizationUnits []string
+	Locality          string
+	State             string
+	Country           string
+}
+
+// CertificateEx is the go form of Certificate
+type CertificateEx struct {
+	Data         string // PEM encoded data
+	Issuer       CertificatePrincipalEx
+	IssuerName   string
+	IssuerCert   *CertificateEx // nil for self signed certificates
+	Subject      CertificatePrincipalEx
+	SubjectName  string
+	SerialNumber string
+	ValidStart   time.Time
+	ValidExpiry  time.Time
+	Fingerprint  string
+	// the javascript certificate, kept to hand it back to electron
+	object *js.Object
+}
+
+func jsStrings(o *js.Object) []string {
+	if isNullish(o) {
+		return nil
+	}
+	ret := make([]string, 0, o.Length())
+	for i := 0; i < o.Length(); i++ {
+		ret = append(ret, o.Index(i).String())
+	}
+	return ret
+}
+
+func jsString(o *js.Object) string {
+	if isNullish(o) {
+		return ""
+	}
+	return o.String()
+}
+
+func wrapCertificatePrincipalEx(o *js.Object) CertificatePrincipalEx {
+	if isNullish(o) {
+		return CertificatePrincipalEx{}
+	}
+	return CertificatePrincipalEx{
+		CommonName:        jsString(o.Get("commonName")),
+		Organizations:     jsStrings(o.Get("organizations")),
+		OrganizationUnits: jsStrings(o.Get("organizationUnits")),
+		Locality:          jsString(o.Get("locality")),
+		State:             jsString(o.Get("state")),
+		Country:           jsString(o.Get("country")),
+	}
+}
+
+// WrapCertificateEx converts the javascript Certificate o, nil when o is
+// null or undefined
+func WrapCertificateEx(o *js.Object) *CertificateEx {
+	if isNullish(o) {
+		return nil
+	}
+	c := &CertificateEx{
+		Data:         jsString(o.Get("data")),
+		Issuer:       wrapCertificatePrincipalEx(o.Get("issuer")),
+		IssuerName:   jsString(o.Get("issuerName")),
+		Subject:      wrapCertificatePrincipalEx(o.Get("subject")),
+		SubjectName:  jsString(o.Get("subjectName")),
+		SerialNumber: jsString(o.Get("serialNumber")),
+		ValidStart:   time.Unix(o.Get("validStart").Int64(), 0),
+		ValidExpiry:  time.Unix(o.Get("validExpiry").Int64(), 0),
+		Fingerprint:  jsString(o.Get("fingerprint")),
+		object:       o,
+	}
+	if issuer := o.Get("issuerCert"); !isNullish(issuer) && issuer != o {
+		c.IssuerCert = WrapCertificateEx(issuer)
+	}
+	return c
+}
+
+// toJs returns the original javascript certificate, or builds one from the
+// go fields for certificates created in go
+func (c *CertificateEx) toJs() interface{} {
+	if c.object != nil {
+		return c.object
+	}
+	principal := func(p CertificatePrincipalEx) js.M {
+		return js.M{
+			"commonName":        p.CommonName,
+			"organizations":     p.Organizations,
+			"organizationUnits": p.OrganizationUnits,
+			"locality":          p.Locality,
+			"state":             p.State,
+			"country":           p.Country,
+		}
+	}
+	m := js.M{
+		"data":         c.Data,
+		"issuer":       principal(c.Issuer),
+		"issuerName":   c.IssuerName,
+		"subject":      principal(c.Subject),
+		"subjectName":  c.SubjectName,
+		"serialNumber": c.SerialNumber,
+		"validStart":   c.ValidStart.Unix(),
+		"validExpiry":  c.ValidExpiry.Unix(),
+		"fingerprint":  c.Fingerprint,
+	}
+	if c.IssuerCert != nil {
+		m["issuerCert"] = c.IssuerCert.toJs()
+	}
+	return m
+}
+
+// X509 parses the PEM data of c
+func (c *CertificateEx) X509() (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(c.Data))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("electron: no PEM certificate in certificate data")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// Chain parses c and its issuers, from c to the root
+func (c *CertificateEx) Chain() ([]*x509.Certificate, error) {
+	chain := []*x509.Certificate{}
+	// issuerCert links back to itself on some platforms
+	for cur := c; cur != nil && len(chain) < 16; cur = cur.IssuerCert {
+		cert, err := cur.X509()
+		if err != nil {
+			return nil, err
+		}
+		if len(chain) > 0 && cert.Equal(chain[len(chain)-1]) {
+			break
+		}
+		chain = append(chain, cert)
+	}
+	return chain, nil
+}
+
+// SPKIHash returns the base64 encoded SHA-256 hash of the subject public key
+// info of cert, the form used to pin certificates
+func SPKIHash(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
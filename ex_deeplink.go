@@ -0,0 +1,271 @@
+package electron
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// LinkHandler handles a deep link, params holds the values of the `{name}`
+// segments of the matching pattern
+type LinkHandler func(u *url.URL, params map[string]string)
+
+type linkRoute struct {
+	scheme   string
+	segments []string
+	handler  LinkHandler
+}
+
+// DeepLinkRouter dispatches the urls and files the application is asked to
+// open to go handlers, whatever the way they arrive: the `open-url` and
+// `open-file` app events on macOS, the command line on Linux and Windows,
+// and the command line of a second instance forwarded by SingleInstance.
+//
+// They are queued until both the app is ready and Start has been called, so
+// that handlers can rely on the windows created at startup.
+type DeepLinkRouter struct {
+	// OnUnhandled is called with the urls and files no handler matches, the
+	// urls of unregistered schemes included
+	OnUnhandled func(target string)
+
+	schemes map[string]bool
+	routes  []*linkRoute
+	files   map[string]func(path string)
+	si      *SingleInstance
+	ready   bool
+	started bool
+	pending []pendingLink
+}
+
+type pendingLink struct {
+	target, wd string
+}
+
+// NewDeepLinkRouterEx creates a router, it must be called synchronously at
+// startup in the main process so that the early macOS events are not missed
+func NewDeepLinkRouterEx() *DeepLinkRouter {
+	r := &DeepLinkRouter{
+		schemes: make(map[string]bool),
+		files:   make(map[string]func(path string)),
+	}
+	app := GetApp()
+	r.ready = app.IsReady()
+	app.On(EvtAppOpenURL, func(args ...*js.Object) {
+		// args: event, url
+		args[0].Call("preventDefault")
+		r.Dispatch(args[1].String(), "")
+	})
+	app.On(EvtAppOpenFile, func(args ...*js.Object) {
+		// args: event, path
+		args[0].Call("preventDefault")
+		r.Dispatch(args[1].String(), "")
+	})
+	app.On(EvtAppReady, func(args ...*js.Object) {
+		r.ready = true
+		r.flush()
+	})
+	return r
+}
+
+// Handle registers h for the urls matching pattern, e.g.
+// `myapp://project/{id}`. The host is the first segment, `{name}` matches
+// any single segment, the query is left to the handler.
+func (r *DeepLinkRouter) Handle(pattern string, h LinkHandler) error {
+	i := strings.Index(pattern, "://")
+	if i <= 0 {
+		return fmt.Errorf("electron: invalid link pattern %q, it needs a scheme", pattern)
+	}
+	scheme := strings.ToLower(pattern[:i])
+	r.schemes[scheme] = true
+	r.routes = append(r.routes, &linkRoute{
+		scheme:   scheme,
+		segments: splitLinkPath(pattern[i+3:]),
+		handler:  h,
+	})
+	return nil
+}
+
+// HandleFile registers h for the files with extension ext, e.g. ".txt",
+// compared case insensitively. An empty ext matches any file.
+func (r *DeepLinkRouter) HandleFile(ext string, h func(path string)) {
+	ext = strings.ToLower(ext)
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	r.files[ext] = h
+}
+
+// RegisterProtocolClients makes the application the default handler of the
+// schemes of the registered patterns, see `app.setAsDefaultProtocolClient`
+func (r *DeepLinkRouter) RegisterProtocolClients() error {
+	app := GetApp()
+	process := js.Global.Get("process")
+	for scheme := range r.schemes {
+		var ok bool
+		if process.Get("defaultApp").Bool() && process.Get("argv").Length() > 1 {
+			// run through the electron binary, the app path has to be passed
+			appPath := require.Invoke("path").Call("resolve", process.Get("argv").Index(1))
+			ok = app.Call("setAsDefaultProtocolClient", scheme, process.Get("execPath"), []interface{}{appPath}).Bool()
+		} else {
+			ok = app.Call("setAsDefaultProtocolClient", scheme).Bool()
+		}
+		if !ok {
+			return fmt.Errorf("electron: could not register as %s:// protocol client", scheme)
+		}
+	}
+	return nil
+}
+
+// Attach routes the arguments forwarded to si by second instances, and
+// makes Start decode the command line with si's flags
+func (r *DeepLinkRouter) Attach(si *SingleInstance) {
+	r.si = si
+	next := si.OnSecondInstance
+	si.OnSecondInstance = func(args *InstanceArgs) {
+		if next != nil {
+			next(args)
+		}
+		r.DispatchArgs(args)
+	}
+}
+
+// Start dispatches the command line of the running process and the targets
+// queued so far, and those coming next as they arrive
+func (r *DeepLinkRouter) Start() {
+	si := r.si
+	if si == nil {
+		si = &SingleInstance{}
+	}
+	r.DispatchArgs(si.Current())
+	r.started = true
+	r.flush()
+}
+
+// DispatchArgs dispatches the positional arguments of args
+func (r *DeepLinkRouter) DispatchArgs(args *InstanceArgs) {
+	for _, a := range args.Args {
+		r.Dispatch(a, args.WorkingDirectory)
+	}
+}
+
+// Dispatch routes target, an url or a file path relative to wd, it is
+// queued when the router is not started yet
+func (r *DeepLinkRouter) Dispatch(target, wd string) {
+	if !r.ready || !r.started {
+		r.pending = append(r.pending, pendingLink{target, wd})
+		return
+	}
+	if !r.route(target, wd) && r.OnUnhandled != nil {
+		r.OnUnhandled(target)
+	}
+}
+
+func (r *DeepLinkRouter) flush() {
+	if !r.ready || !r.started {
+		return
+	}
+	pending := r.pending
+	r.pending = nil
+	for _, p := range pending {
+		r.Dispatch(p.target, p.wd)
+	}
+}
+
+func (r *DeepLinkRouter) route(target, wd string) bool {
+	if i := strings.Index(target, ":"); i > 1 && isLinkScheme(target[:i]) {
+		if r.schemes[strings.ToLower(target[:i])] {
+			u, err := url.Parse(target)
+			if err != nil {
+				return false
+			}
+			return r.routeURL(u)
+		}
+		if strings.HasPrefix(target[i:], "://") {
+			// an url of another scheme, not a file
+			return false
+		}
+	}
+	if strings.HasPrefix(target, "-") {
+		return false
+	}
+	path := target
+	if wd != "" {
+		path = require.Invoke("path").Call("resolve", wd, target).String()
+	}
+	ext := strings.ToLower(require.Invoke("path").Call("extname", path).String())
+	if h, ok := r.files[ext]; ok {
+		h(path)
+		return true
+	}
+	if h, ok := r.files[""]; ok {
+		h(path)
+		return true
+	}
+	return false
+}
+
+// isLinkScheme reports whether s is a valid url scheme, the one letter
+// ones are left to the windows drive letters
+func isLinkScheme(s string) bool {
+	for i, c := range s {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		case i > 0 && (c >= '0' && c <= '9' || c == '+' || c == '-' || c == '.'):
+		default:
+			return false
+		}
+	}
+	return len(s) > 1
+}
+
+func (r *DeepLinkRouter) routeURL(u *url.URL) bool {
+	var segments []string
+	if u.Opaque != "" {
+		// myapp:project/42
+		segments = splitLinkPath(u.Opaque)
+	} else {
+		segments = splitLinkPath(u.Host + "/" + u.EscapedPath())
+	}
+	scheme := strings.ToLower(u.Scheme)
+	for _, route := range r.routes {
+		if route.scheme != scheme {
+			continue
+		}
+		if params, ok := route.match(segments); ok {
+			route.handler(u, params)
+			return true
+		}
+	}
+	return false
+}
+
+func (route *linkRoute) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(route.segments) {
+		return nil, false
+	}
+	params := map[string]string{}
+	for i, p := range route.segments {
+		if strings.HasPrefix(p, "{") && strings.HasSuffix(p, "}") {
+			params[p[1:len(p)-1]] = segments[i]
+		} else if p != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func splitLinkPath(p string) []string {
+	ret := []string{}
+	for _, s := range strings.Split(p, "/") {
+		if s == "" {
+			continue
+		}
+		if u, err := url.PathUnescape(s); err == nil {
+			s = u
+		}
+		ret = append(ret, s)
+	}
+	return ret
+}
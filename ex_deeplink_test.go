@@ -0,0 +1,89 @@
+package electron
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestDeepLinkRoute(t *testing.T) {
+	r := &DeepLinkRouter{
+		schemes: make(map[string]bool),
+		files:   make(map[string]func(path string)),
+	}
+	var got string
+	var params map[string]string
+	handle := func(pattern string) {
+		if err := r.Handle(pattern, func(u *url.URL, p map[string]string) {
+			got, params = pattern, p
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	handle("myapp://project/{id}")
+	handle("myapp://project/{id}/file/{name}")
+	handle("myapp://settings")
+	handle("Other+App://open")
+	r.HandleFile("", func(path string) {
+		got = "file " + path
+	})
+	tests := []struct {
+		target  string
+		pattern string // empty when unhandled
+		params  map[string]string
+	}{
+		{"myapp://project/42", "myapp://project/{id}", map[string]string{"id": "42"}},
+		{"MYAPP://project/42/", "myapp://project/{id}", map[string]string{"id": "42"}},
+		{"myapp://project/42?tab=files", "myapp://project/{id}", map[string]string{"id": "42"}},
+		{"myapp://project/a%20b/file/c%2Fd", "myapp://project/{id}/file/{name}", map[string]string{"id": "a b", "name": "c/d"}},
+		{"myapp:project/7", "myapp://project/{id}", map[string]string{"id": "7"}},
+		{"myapp://settings", "myapp://settings", map[string]string{}},
+		{"other+app://open", "Other+App://open", map[string]string{}},
+		{"myapp://project", "", nil},
+		{"myapp://unknown/1", "", nil},
+		// urls of other schemes are not files
+		{"https://example.com/x.txt", "", nil},
+		{"otherapp://project/42", "", nil},
+		{"--flag", "", nil},
+	}
+	for _, tt := range tests {
+		got, params = "", nil
+		ok := r.route(tt.target, "/tmp")
+		if ok != (tt.pattern != "") || got != tt.pattern {
+			t.Errorf("route(%q) = %v with %q, want %q", tt.target, ok, got, tt.pattern)
+			continue
+		}
+		if ok && !reflect.DeepEqual(params, tt.params) {
+			t.Errorf("route(%q) params = %v, want %v", tt.target, params, tt.params)
+		}
+	}
+}
+
+func TestDeepLinkHandleInvalid(t *testing.T) {
+	r := &DeepLinkRouter{schemes: make(map[string]bool)}
+	for _, p := range []string{"project/{id}", "://project", ""} {
+		if err := r.Handle(p, func(*url.URL, map[string]string) {}); err == nil {
+			t.Errorf("Handle(%q) succeeded, want an error", p)
+		}
+	}
+}
+
+func TestIsLinkScheme(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"myapp", true},
+		{"web+app", true},
+		{"x-y.z9", true},
+		{"C", false}, // windows drive letter
+		{"9app", false},
+		{"my app", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isLinkScheme(tt.in); got != tt.want {
+			t.Errorf("isLinkScheme(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
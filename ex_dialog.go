@@ -1,6 +1,11 @@
 package electron
 
-import "github.com/gopherjs/gopherjs/js"
+import (
+	"errors"
+	"strings"
+
+	"github.com/gopherjs/gopherjs/js"
+)
 
 // FileFilterEx wraps
 type FileFilterEx struct {
@@ -64,6 +69,9 @@ func (o DialogOptionOpen) toJs() js.M {
 		}
 		ret["properties"] = p
 	}
+	if o.NormalizeAccessKeys {
+		ret["normalizeAccessKeys"] = o.NormalizeAccessKeys
+	}
 	return ret
 }
 
@@ -200,13 +208,25 @@ const (
 type DialogOptionMessage struct {
 	Type      string       // type String (optional) - Can be "none", "info", "error", "question" or "warning". On Windows, “question” displays the same icon as “info”, unless you set an icon using the “icon” option.
 	Buttons   []string     // buttons String[] (optional) - Array of texts for buttons. On Windows, an empty array will result in one button labeled “OK”.
-	DefaultID int          // defaultId Integer (optional) - Index of the button in the buttons array which will be selected by default when the message box opens.
+	DefaultID int          // defaultId Integer (optional) - Index of the button in the buttons array which will be selected by default when the message box opens. Left to electron's default when 0, unless HasDefaultID is set.
 	Title     string       // title String (optional) - Title of the message box, some platforms will not show it.
 	Message   string       // message String - Content of the message box.
 	Detail    string       // detail String (optional) - Extra information of the message.
 	Icon      *NativeImage // icon NativeImage (optional)
-	CancelID  int          // cancelId Integer (optional) - The value will be returned when user cancels the dialog instead of clicking the buttons of the dialog. By default it is the index of the buttons that have “cancel” or “no” as label, or 0 if there is no such buttons. On macOS and Windows the index of the “Cancel” button will always be used as cancelId even if it is specified.
+	CancelID  int          // cancelId Integer (optional) - Left to electron's default when 0, unless HasCancelID is set. The value will be returned when user cancels the dialog instead of clicking the buttons of the dialog. By default it is the index of the buttons that have “cancel” or “no” as label, or 0 if there is no such buttons. On macOS and Windows the index of the “Cancel” button will always be used as cancelId even if it is specified.
 	NoLink    bool         // noLink Boolean (optional) - On Windows Electron will try to figure out which one of the buttons are common buttons (like “Cancel” or “Yes”), and show the others as command links in the dialog. This can make the dialog appear in the style of modern Windows apps. If you don’t like this behavior, you can set noLink to true.
+	// normalizeAccessKeys Boolean (optional) - Normalize the keyboard access keys across platforms.
+	// Default is false. See DialogOptionOpen.
+	NormalizeAccessKeys bool
+	// checkboxLabel String (optional) - If provided, the message box will include a checkbox with the given label.
+	// The checkbox state is only reported by the asynchronous variants. Needs electron >= 1.6.
+	CheckboxLabel string
+	// checkboxChecked Boolean (optional) - Initial checked state of the checkbox. false by default.
+	CheckboxChecked bool
+	// HasDefaultID and HasCancelID send DefaultID and CancelID even when 0,
+	// to make the first button the default or the cancel one
+	HasDefaultID bool
+	HasCancelID  bool
 }
 
 func (o DialogOptionMessage) toJs() js.M {
@@ -221,8 +241,8 @@ func (o DialogOptionMessage) toJs() js.M {
 		}
 		m["buttons"] = s
 	}
-	if o.DefaultID != 0 {
-		m["defaultID"] = o.DefaultID
+	if o.DefaultID != 0 || o.HasDefaultID {
+		m["defaultId"] = o.DefaultID
 	}
 	if o.Title != "" {
 		m["title"] = o.Title
@@ -236,9 +256,19 @@ func (o DialogOptionMessage) toJs() js.M {
 	if o.Icon != nil {
 		m["icon"] = o.Icon
 	}
+	if o.CancelID != 0 || o.HasCancelID {
+		m["cancelId"] = o.CancelID
+	}
 	if o.NoLink != false {
 		m["noLink"] = o.NoLink
 	}
+	if o.NormalizeAccessKeys {
+		m["normalizeAccessKeys"] = o.NormalizeAccessKeys
+	}
+	if o.CheckboxLabel != "" {
+		m["checkboxLabel"] = o.CheckboxLabel
+		m["checkboxChecked"] = o.CheckboxChecked
+	}
 	return m
 }
 
@@ -282,3 +312,201 @@ func (d *DialogModule) ShowMessageEx(title, msg string) {
 		Message: msg,
 	})
 }
+
+// OpenDialogResult is the result of ShowOpenDialogAsyncEx
+type OpenDialogResult struct {
+	FilePaths []string
+	Cancelled bool // the dialog was dismissed, FilePaths is nil
+}
+
+// SaveDialogResult is the result of ShowSaveDialogAsyncEx
+type SaveDialogResult struct {
+	FilePath  string
+	Cancelled bool // the dialog was dismissed, FilePath is empty
+}
+
+// MessageResult is the result of ShowMessageBoxAsyncEx
+type MessageResult struct {
+	Button    int  // index of the clicked button
+	Cancelled bool // the dialog was cancelled or the cancel button was clicked
+	// CheckboxChecked is the state of the checkbox when CheckboxLabel is set,
+	// only reported by the asynchronous variants
+	CheckboxChecked bool
+}
+
+// dialogArgs builds the `[browserWindow, ]options, callback` arguments
+func dialogArgs(parent *BrowserWindow, opts js.M, callback interface{}) []interface{} {
+	if parent != nil {
+		return []interface{}{parent.Object, opts, callback}
+	}
+	return []interface{}{opts, callback}
+}
+
+// ShowOpenDialogAsyncEx shows an open dialog without blocking the process,
+// fn is called with the selected paths once the dialog is closed.
+// parent can be nil, otherwise the dialog is attached to it as a sheet or
+// modal window.
+//
+// fn is called from the javascript callback and must not block, use
+// ShowOpenDialogChanEx to receive the result from a goroutine.
+func (d *DialogModule) ShowOpenDialogAsyncEx(opt DialogOptionOpen, parent *BrowserWindow, fn func(r OpenDialogResult)) {
+	d.Call("showOpenDialog", dialogArgs(parent, opt.toJs(), func(filePaths *js.Object) {
+		r := OpenDialogResult{}
+		if isNullish(filePaths) {
+			r.Cancelled = true
+		} else {
+			r.FilePaths = []string{}
+			for i := 0; i < filePaths.Length(); i++ {
+				r.FilePaths = append(r.FilePaths, filePaths.Index(i).String())
+			}
+		}
+		fn(r)
+	})...)
+}
+
+// ShowOpenDialogChanEx is ShowOpenDialogAsyncEx delivering the result on a channel
+func (d *DialogModule) ShowOpenDialogChanEx(opt DialogOptionOpen, parent *BrowserWindow) <-chan OpenDialogResult {
+	ch := make(chan OpenDialogResult, 1)
+	d.ShowOpenDialogAsyncEx(opt, parent, func(r OpenDialogResult) {
+		ch <- r
+	})
+	return ch
+}
+
+// ShowSaveDialogAsyncEx shows a save dialog without blocking the process,
+// see ShowOpenDialogAsyncEx.
+func (d *DialogModule) ShowSaveDialogAsyncEx(opt DialogOptionSave, parent *BrowserWindow, fn func(r SaveDialogResult)) {
+	d.Call("showSaveDialog", dialogArgs(parent, opt.toJs(), func(filename *js.Object) {
+		r := SaveDialogResult{}
+		if isNullish(filename) {
+			r.Cancelled = true
+		} else {
+			r.FilePath = filename.String()
+		}
+		fn(r)
+	})...)
+}
+
+// ShowSaveDialogChanEx is ShowSaveDialogAsyncEx delivering the result on a channel
+func (d *DialogModule) ShowSaveDialogChanEx(opt DialogOptionSave, parent *BrowserWindow) <-chan SaveDialogResult {
+	ch := make(chan SaveDialogResult, 1)
+	d.ShowSaveDialogAsyncEx(opt, parent, func(r SaveDialogResult) {
+		ch <- r
+	})
+	return ch
+}
+
+// isCancel reports whether button is the one electron returns when the
+// message box is cancelled on platform (as `process.platform`, the current
+// one when empty): the button labeled "cancel" on macOS and Windows,
+// otherwise cancelId when set, otherwise the first button labeled "cancel"
+// or "no".
+func (o DialogOptionMessage) isCancel(button int, platform string) bool {
+	if platform == "" {
+		platform = js.Global.Get("process").Get("platform").String()
+	}
+	cancel, cancelOrNo := -1, -1
+	for i, b := range o.Buttons {
+		l := strings.ToLower(strings.Replace(b, "&", "", -1))
+		if l == "cancel" && cancel < 0 {
+			cancel = i
+		}
+		if (l == "cancel" || l == "no") && cancelOrNo < 0 {
+			cancelOrNo = i
+		}
+	}
+	switch {
+	case cancel >= 0 && (platform == "darwin" || platform == "win32"):
+		return button == cancel
+	case o.CancelID != 0 || o.HasCancelID:
+		return button == o.CancelID
+	}
+	return cancelOrNo >= 0 && button == cancelOrNo
+}
+
+// ShowMessageBoxAsyncEx shows a message box without blocking the process,
+// see ShowOpenDialogAsyncEx.
+func (d *DialogModule) ShowMessageBoxAsyncEx(opt DialogOptionMessage, parent *BrowserWindow, fn func(r MessageResult)) {
+	d.Call("showMessageBox", dialogArgs(parent, opt.toJs(), func(response int, checkboxChecked *js.Object) {
+		fn(MessageResult{
+			Button:          response,
+			Cancelled:       opt.isCancel(response, ""),
+			CheckboxChecked: !isNullish(checkboxChecked) && checkboxChecked.Bool(),
+		})
+	})...)
+}
+
+// ShowMessageBoxChanEx is ShowMessageBoxAsyncEx delivering the result on a channel
+func (d *DialogModule) ShowMessageBoxChanEx(opt DialogOptionMessage, parent *BrowserWindow) <-chan MessageResult {
+	ch := make(chan MessageResult, 1)
+	d.ShowMessageBoxAsyncEx(opt, parent, func(r MessageResult) {
+		ch <- r
+	})
+	return ch
+}
+
+// ShowMessageBoxResultEx is ShowMessageBoxEx returning a MessageResult
+func (d *DialogModule) ShowMessageBoxResultEx(opt DialogOptionMessage, bw ...*BrowserWindow) MessageResult {
+	button := d.ShowMessageBoxEx(opt, bw...)
+	return MessageResult{
+		Button:    button,
+		Cancelled: opt.isCancel(button, ""),
+	}
+}
+
+// ShowErrorBoxEx dialog.showErrorBox(title, content)
+// title String - The title to display in the error box
+// content String - The text content to display in the error box
+//
+// Displays a modal dialog that shows an error message.
+//
+// This API can be called safely before the ready event the app module emits,
+// it is usually used to report errors in early stage of startup. If called
+// before the app ready event on Linux, the message will be emitted to stderr,
+// and no GUI dialog will appear.
+func (d *DialogModule) ShowErrorBoxEx(title, content string) {
+	d.Call("showErrorBox", title, content)
+}
+
+// DialogOptionCertificateTrust options for ShowCertificateTrustDialogEx
+type DialogOptionCertificateTrust struct {
+	Certificate *CertificateEx // certificate Certificate - The certificate to trust/import.
+	Message     string         // message String - The message to display to the user.
+}
+
+func (o DialogOptionCertificateTrust) toJs() js.M {
+	m := make(js.M)
+	if o.Certificate != nil {
+		m["certificate"] = o.Certificate.toJs()
+	}
+	m["message"] = o.Message
+	return m
+}
+
+// ShowCertificateTrustDialogEx dialog.showCertificateTrustDialog([browserWindow, ]options, callback) macOS
+// browserWindow BrowserWindow (optional)
+// options Object
+// 	certificate Certificate - The certificate to trust/import.
+// 	message String - The message to display to the user.
+// callback Function
+//
+// Displays a modal dialog that shows a message and certificate information,
+// and gives the user the option of trusting/importing the certificate.
+// parent can be nil, fn is called once the dialog is closed and must not block.
+//
+// An error is returned when the running electron does not provide the dialog,
+// it appeared in electron 1.5 and is only available on macOS.
+func (d *DialogModule) ShowCertificateTrustDialogEx(opt DialogOptionCertificateTrust, parent *BrowserWindow, fn func()) error {
+	if isNullish(d.Get("showCertificateTrustDialog")) {
+		return errors.New("electron: dialog.showCertificateTrustDialog is not available")
+	}
+	if opt.Certificate == nil {
+		return errors.New("electron: certificate trust dialog needs a certificate")
+	}
+	d.Call("showCertificateTrustDialog", dialogArgs(parent, opt.toJs(), func() {
+		if fn != nil {
+			fn()
+		}
+	})...)
+	return nil
+}
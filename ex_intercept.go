@@ -0,0 +1,412 @@
+package electron
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// chromium net error codes usable with InterceptError
+const (
+	NetErrFailed       = -2
+	NetErrAborted      = -3
+	NetErrFileNotFound = -6
+	NetErrAccessDenied = -10
+	NetErrBlocked      = -20
+)
+
+// InterceptRequest is a request seen by the interception middlewares
+type InterceptRequest struct {
+	URL      *url.URL
+	Method   string
+	Referrer string
+	Header   http.Header // empty with the electron versions not giving them
+	Body     []byte
+	Object   *js.Object // the request object given by electron
+}
+
+type interceptKind int
+
+const (
+	interceptPass interceptKind = iota
+	interceptData
+	interceptFile
+	interceptRedirect
+	interceptError
+	interceptFetch
+)
+
+// InterceptResponse is the answer of a middleware, created by InterceptFile,
+// InterceptString, InterceptBuffer, InterceptRedirect, InterceptError or
+// InterceptFetch
+type InterceptResponse struct {
+	kind     interceptKind
+	mimeType string
+	data     []byte
+	target   string // file path or redirect url
+	code     int
+	header   http.Header
+}
+
+// InterceptFile answers with the content of the file at path, its mime type
+// is deduced from its extension
+func InterceptFile(path string) *InterceptResponse {
+	return &InterceptResponse{kind: interceptFile, target: path}
+}
+
+// InterceptString answers with s
+func InterceptString(s, mimeType string) *InterceptResponse {
+	return &InterceptResponse{kind: interceptData, data: []byte(s), mimeType: mimeType}
+}
+
+// InterceptBuffer answers with b
+func InterceptBuffer(b []byte, mimeType string) *InterceptResponse {
+	return &InterceptResponse{kind: interceptData, data: b, mimeType: mimeType}
+}
+
+// InterceptRedirect redirects to target. Without
+// `protocol.interceptStreamProtocol` there is no redirect status, target is
+// then served in place of the request when it has the same origin, and the
+// request fails otherwise.
+func InterceptRedirect(target string) *InterceptResponse {
+	return &InterceptResponse{kind: interceptRedirect, target: target}
+}
+
+// WithHeader adds h to the response headers, they are dropped without
+// `protocol.interceptStreamProtocol`
+func (r *InterceptResponse) WithHeader(h http.Header) *InterceptResponse {
+	if r.header == nil {
+		r.header = http.Header{}
+	}
+	for k, v := range h {
+		r.header[k] = v
+	}
+	return r
+}
+
+// InterceptFetch answers an http or https request by doing it again with
+// node's http module. The request then bypasses the session: its cookies,
+// cache, proxy, webRequest rules and certificate verification, including a
+// CertificatePinner, do not apply. Other schemes are passed through.
+func InterceptFetch() *InterceptResponse {
+	return &InterceptResponse{kind: interceptFetch}
+}
+
+// InterceptError fails the request with a chromium net error code, e.g.
+// NetErrBlocked
+func InterceptError(code int) *InterceptResponse {
+	return &InterceptResponse{kind: interceptError, code: code}
+}
+
+// InterceptMiddleware inspects a request, it returns nil to pass the request
+// to the next middleware, or the response to give. A middleware panicking
+// fails the request with NetErrFailed.
+type InterceptMiddleware func(req *InterceptRequest) *InterceptResponse
+
+// InterceptHandle identifies a middleware added by Intercept
+type InterceptHandle struct {
+	scheme string
+	order  int
+	mw     InterceptMiddleware
+}
+
+type protocolInterceptor struct {
+	scheme string
+	chain  []*InterceptHandle
+}
+
+var interceptors = map[string]*protocolInterceptor{}
+
+// ErrInterceptHTTP is returned by Intercept for the http and https schemes
+// without `protocol.interceptStreamProtocol`, the buffer protocol loses the
+// status and headers of the requests passed through
+var ErrInterceptHTTP = errors.New("electron: intercepting http and https needs protocol.interceptStreamProtocol")
+
+// Intercept adds mw to the middlewares of scheme, e.g. "https" or "file".
+// Middlewares run by increasing order, then by registration order, the
+// requests none of them answers are passed through: file urls are read from
+// the disk, the other schemes fail with NetErrFailed as they can not go back
+// to the session once intercepted. A middleware can answer InterceptFetch to
+// fetch http and https requests outside of the session. Intercepting http
+// and https fails with ErrInterceptHTTP on the electron versions without
+// `protocol.interceptStreamProtocol`.
+//
+// The scheme is intercepted when its first middleware is added, it must be
+// called after the app is ready, from a goroutine as it waits for electron.
+func (p *ProtocolModule) Intercept(scheme string, order int, mw InterceptMiddleware) (*InterceptHandle, error) {
+	h := &InterceptHandle{scheme: scheme, order: order, mw: mw}
+	pi, ok := interceptors[scheme]
+	if !ok {
+		if (scheme == "http" || scheme == "https") && !p.streams() {
+			return nil, ErrInterceptHTTP
+		}
+		pi = &protocolInterceptor{scheme: scheme}
+		if err := p.protocolCall(p.interceptMethod(), scheme, protocolInterceptHandler(pi, p.streams())); err != nil {
+			return nil, err
+		}
+		interceptors[scheme] = pi
+	}
+	i := len(pi.chain)
+	for i > 0 && pi.chain[i-1].order > order {
+		i--
+	}
+	pi.chain = append(pi.chain, nil)
+	copy(pi.chain[i+1:], pi.chain[i:])
+	pi.chain[i] = h
+	return h, nil
+}
+
+// RemoveIntercept removes the middleware of h, the scheme is no longer
+// intercepted once it has no middleware left
+func (p *ProtocolModule) RemoveIntercept(h *InterceptHandle) error {
+	pi, ok := interceptors[h.scheme]
+	if !ok {
+		return nil
+	}
+	for i, x := range pi.chain {
+		if x == h {
+			pi.chain = append(pi.chain[:i:i], pi.chain[i+1:]...)
+			break
+		}
+	}
+	if len(pi.chain) > 0 {
+		return nil
+	}
+	delete(interceptors, h.scheme)
+	return p.protocolCall("uninterceptProtocol", h.scheme)
+}
+
+func (p *ProtocolModule) streams() bool {
+	return !isNullish(p.Get("interceptStreamProtocol"))
+}
+
+func (p *ProtocolModule) interceptMethod() string {
+	if p.streams() {
+		return "interceptStreamProtocol"
+	}
+	return "interceptBufferProtocol"
+}
+
+// protocolCall calls a protocol method taking a completion callback as last
+// argument and waits for it
+func (p *ProtocolModule) protocolCall(method string, args ...interface{}) error {
+	done := make(chan error, 1)
+	args = append(args, func(err *js.Object) {
+		if isNullish(err) {
+			done <- nil
+			return
+		}
+		done <- fmt.Errorf("electron: %s %v: %s", method, args[0], err.Get("message"))
+	})
+	if err := jsTry(func() { p.Call(method, args...) }); err != nil {
+		return err
+	}
+	return <-done
+}
+
+func newInterceptRequest(o *js.Object) (*InterceptRequest, error) {
+	req, err := protocolRequest(o)
+	if err != nil {
+		return nil, err
+	}
+	ir := &InterceptRequest{
+		URL:      req.URL,
+		Method:   req.Method,
+		Referrer: jsString(o.Get("referrer")),
+		Header:   req.Header,
+		Object:   o,
+	}
+	ir.Header.Del("Referer")
+	ir.Body, err = ioutil.ReadAll(req.Body)
+	return ir, err
+}
+
+func protocolInterceptHandler(pi *protocolInterceptor, stream bool) func(o, callback *js.Object) {
+	return func(o, callback *js.Object) {
+		chain := append([]*InterceptHandle(nil), pi.chain...)
+		// middlewares and pass through may block
+		go func() {
+			req, err := newInterceptRequest(o)
+			if err != nil {
+				callback.Invoke(NetErrFailed)
+				return
+			}
+			resp := runIntercept(chain, req)
+			w, code := interceptResult(req, resp, stream)
+			if code == 0 {
+				for k, v := range resp.header {
+					w.header[k] = v
+				}
+			}
+			switch {
+			case code != 0:
+				callback.Invoke(code)
+			case stream:
+				callback.Invoke(w.streamResponse())
+			default:
+				callback.Invoke(w.bufferResponse())
+			}
+		}()
+	}
+}
+
+func runIntercept(chain []*InterceptHandle, req *InterceptRequest) (resp *InterceptResponse) {
+	defer func() {
+		if r := recover(); r != nil {
+			resp = InterceptError(NetErrFailed)
+		}
+	}()
+	for _, h := range chain {
+		if resp = h.mw(req); resp != nil {
+			return resp
+		}
+	}
+	return &InterceptResponse{kind: interceptPass}
+}
+
+// interceptResult turns resp into the response to give to electron, or into
+// a net error code
+func interceptResult(req *InterceptRequest, resp *InterceptResponse, stream bool) (*protocolResponse, int) {
+	w := newProtocolResponse()
+	switch resp.kind {
+	case interceptError:
+		return nil, resp.code
+	case interceptData:
+		w.status = http.StatusOK
+		w.header.Set("Content-Type", resp.mimeType)
+		w.body.Write(resp.data)
+		return w, 0
+	case interceptFile:
+		return fileResponse(resp.target)
+	case interceptRedirect:
+		if stream {
+			w.status = http.StatusFound
+			w.header.Set("Location", resp.target)
+			return w, 0
+		}
+		u, err := req.URL.Parse(resp.target)
+		if err != nil {
+			return nil, NetErrFailed
+		}
+		if urlOrigin(u) != urlOrigin(req.URL) {
+			// served under the origin of req otherwise
+			return nil, NetErrBlocked
+		}
+		to := *req
+		to.URL, to.Method, to.Body = u, "GET", nil
+		return passThrough(&to)
+	case interceptFetch:
+		if req.URL.Scheme == "http" || req.URL.Scheme == "https" {
+			return nodeFetch(req)
+		}
+	}
+	return passThrough(req)
+}
+
+func passThrough(req *InterceptRequest) (*protocolResponse, int) {
+	if req.URL.Scheme == "file" {
+		return fileResponse(fileURLPath(req.URL))
+	}
+	return nil, NetErrFailed
+}
+
+var windowsDrivePath = regexp.MustCompile(`^/[a-zA-Z]:`)
+
+// fileURLPath returns the local path of a file url
+func fileURLPath(u *url.URL) string {
+	p := u.Path
+	if windowsDrivePath.MatchString(p) {
+		p = p[1:]
+	}
+	return p
+}
+
+func fileResponse(name string) (*protocolResponse, int) {
+	var b []byte
+	err := jsTry(func() {
+		b = bytesFromJs(require.Invoke("fs").Call("readFileSync", name))
+	})
+	if err != nil {
+		return nil, NetErrFileNotFound
+	}
+	w := newProtocolResponse()
+	w.status = http.StatusOK
+	ext := strings.ToLower(path.Ext(strings.Replace(name, "\\", "/", -1)))
+	if ct, ok := assetMimeTypes[ext]; ok {
+		w.header.Set("Content-Type", ct)
+	} else if ct := mime.TypeByExtension(ext); ct != "" {
+		w.header.Set("Content-Type", ct)
+	} else {
+		w.header.Set("Content-Type", http.DetectContentType(b))
+	}
+	w.body.Write(b)
+	return w, 0
+}
+
+// nodeFetch does req with node's http or https module, outside of the
+// session
+func nodeFetch(req *InterceptRequest) (*protocolResponse, int) {
+	type result struct {
+		w    *protocolResponse
+		code int
+	}
+	// error may follow end
+	done := make(chan result, 2)
+	opt := require.Invoke("url").Call("parse", req.URL.String())
+	opt.Set("method", req.Method)
+	headers := js.M{}
+	for k, v := range req.Header {
+		headers[k] = strings.Join(v, ", ")
+	}
+	if req.Referrer != "" {
+		headers["Referer"] = req.Referrer
+	}
+	// node does not decompress
+	headers["Accept-Encoding"] = "identity"
+	opt.Set("headers", headers)
+	err := jsTry(func() {
+		creq := require.Invoke(req.URL.Scheme).Call("request", opt, func(res *js.Object) {
+			w := newProtocolResponse()
+			w.status = res.Get("statusCode").Int()
+			h := res.Get("headers")
+			keys := js.Global.Get("Object").Call("keys", h)
+			for i := 0; i < keys.Length(); i++ {
+				k := keys.Index(i).String()
+				v := h.Get(k)
+				if js.Global.Get("Array").Call("isArray", v).Bool() {
+					for _, s := range jsStrings(v) {
+						w.header.Add(k, s)
+					}
+				} else {
+					w.header.Set(k, v.String())
+				}
+			}
+			res.Call("on", "data", func(chunk *js.Object) {
+				w.body.Write(bytesFromJs(chunk))
+			})
+			res.Call("on", "end", func() {
+				done <- result{w: w}
+			})
+		})
+		creq.Call("on", "error", func(err *js.Object) {
+			done <- result{code: NetErrFailed}
+		})
+		if len(req.Body) > 0 {
+			creq.Call("write", bytesToBuffer(req.Body))
+		}
+		creq.Call("end")
+	})
+	if err != nil {
+		return nil, NetErrFailed
+	}
+	r := <-done
+	return r.w, r.code
+}
@@ -0,0 +1,150 @@
+package electron
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// toJsValue converts a go value into a plain javascript value by
+// round-tripping it through JSON, which is what electron IPC does anyway
+func toJsValue(v interface{}) (*js.Object, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return js.Global.Get("JSON").Call("parse", string(b)), nil
+}
+
+// fromJsValue decodes a plain javascript value into the go value pointed by v
+func fromJsValue(o *js.Object, v interface{}) error {
+	if isNullish(o) {
+		return json.Unmarshal([]byte("null"), v)
+	}
+	s := js.Global.Get("JSON").Call("stringify", o)
+	if isNullish(s) {
+		return json.Unmarshal([]byte("null"), v)
+	}
+	return json.Unmarshal([]byte(s.String()), v)
+}
+
+// isNullish reports whether o is javascript null or undefined
+func isNullish(o *js.Object) bool {
+	return o == nil || o == js.Undefined
+}
+
+// ErrSyncCallInMain is returned by CallSyncEx when called from the main
+// process, where `ipcRenderer.sendSync` would never be answered.
+var ErrSyncCallInMain = errors.New("electron: synchronous IPC call from the main process would deadlock")
+
+// ErrSyncHandlerInRenderer is returned by HandleSyncEx when called from a
+// renderer process, where the handler would run through `remote` and a
+// CallSyncEx from the same renderer would deadlock.
+var ErrSyncHandlerInRenderer = errors.New("electron: synchronous IPC handlers must be registered in the main process")
+
+// SyncCallError is returned by CallSyncEx when the main process handler
+// failed or no handler answered the call.
+type SyncCallError struct {
+	Channel string
+	Message string
+}
+
+func (e *SyncCallError) Error() string {
+	return fmt.Sprintf("electron: sync call %q: %s", e.Channel, e.Message)
+}
+
+// syncHandlers keeps the channels handled by HandleSyncEx, there can be only
+// one `event.returnValue` per call
+var syncHandlers = make(map[string]*syncHandler)
+
+type syncHandler struct {
+	listener func(args ...*js.Object)
+}
+
+// HandleSyncEx registers h in the main process to answer `ipcRenderer.sendSync`
+// calls on channel. h receives the first argument sent by the renderer and
+// its return values are set into `event.returnValue` as
+//
+//	{value: <v as JSON>, error: "<err.Error()>"}
+//
+// which CallSyncEx decodes back. h runs inside the ipcMain listener, so it
+// must not block, the renderer is frozen until it returns.
+//
+// The returned function removes the handler. ErrSyncHandlerInRenderer is
+// returned outside of the main process.
+func HandleSyncEx(channel string, h func(arg *js.Object) (interface{}, error)) (remove func(), err error) {
+	if !IsMainProcess() {
+		return nil, ErrSyncHandlerInRenderer
+	}
+	if _, ok := syncHandlers[channel]; ok {
+		return nil, fmt.Errorf("electron: sync channel %q already handled", channel)
+	}
+	ipc := Get("ipcMain")
+	listener := func(args ...*js.Object) {
+		// args: event, arg
+		event := args[0]
+		var arg *js.Object
+		if len(args) > 1 {
+			arg = args[1]
+		}
+		event.Set("returnValue", syncReply(h, arg))
+	}
+	ipc.Call("on", channel, listener)
+	sh := &syncHandler{listener: listener}
+	syncHandlers[channel] = sh
+	return func() {
+		// a handler registered again since then is left alone
+		if syncHandlers[channel] != sh {
+			return
+		}
+		ipc.Call("removeListener", channel, listener)
+		delete(syncHandlers, channel)
+	}, nil
+}
+
+func syncReply(h func(arg *js.Object) (interface{}, error), arg *js.Object) (reply js.M) {
+	reply = js.M{"value": nil, "error": ""}
+	defer func() {
+		if r := recover(); r != nil {
+			reply["error"] = fmt.Sprint("panic: ", r)
+		}
+	}()
+	v, err := h(arg)
+	if err != nil {
+		reply["error"] = err.Error()
+		return
+	}
+	o, err := toJsValue(v)
+	if err != nil {
+		reply["error"] = err.Error()
+		return
+	}
+	reply["value"] = o
+	return
+}
+
+// CallSyncEx sends arg on channel with `ipcRenderer.sendSync` and decodes the
+// value returned by the HandleSyncEx handler into reply, which must be a
+// pointer or nil. Errors returned by the handler are reported as *SyncCallError.
+func CallSyncEx(channel string, arg interface{}, reply interface{}) error {
+	if IsMainProcess() {
+		return ErrSyncCallInMain
+	}
+	a, err := toJsValue(arg)
+	if err != nil {
+		return err
+	}
+	out := Get("ipcRenderer").Call("sendSync", channel, a)
+	if isNullish(out) || isNullish(out.Get("error")) {
+		return &SyncCallError{Channel: channel, Message: "no HandleSyncEx handler answered"}
+	}
+	if msg := out.Get("error").String(); msg != "" {
+		return &SyncCallError{Channel: channel, Message: msg}
+	}
+	if reply == nil {
+		return nil
+	}
+	return fromJsValue(out.Get("value"), reply)
+}
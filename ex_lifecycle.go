@@ -0,0 +1,247 @@
+package electron
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// QuitPolicy tells what happens when all the windows have been closed
+type QuitPolicy int
+
+// quit policies
+const (
+	// QuitOnLastWindowExceptDarwin quits when the last window is closed, except
+	// on macOS where applications stay active until the user quits explicitly
+	QuitOnLastWindowExceptDarwin QuitPolicy = iota
+	// QuitOnLastWindow always quits when the last window is closed
+	QuitOnLastWindow
+	// KeepRunning keeps the application running without windows, e.g. in
+	// the tray, until Quit is called
+	KeepRunning
+)
+
+// ShutdownPhase is the app event during which a shutdown hook runs
+type ShutdownPhase int
+
+// shutdown phases
+const (
+	// PhaseBeforeQuit runs on `before-quit`, before the windows are closed
+	PhaseBeforeQuit ShutdownPhase = iota
+	// PhaseWillQuit runs on `will-quit`, after all the windows have been closed
+	PhaseWillQuit
+)
+
+// ShutdownTimeoutError is reported when a shutdown hook does not return in time
+type ShutdownTimeoutError struct {
+	Name    string
+	Timeout time.Duration
+}
+
+func (e *ShutdownTimeoutError) Error() string {
+	return fmt.Sprintf("electron: shutdown hook %q timed out after %v", e.Name, e.Timeout)
+}
+
+type shutdownHook struct {
+	name    string
+	order   int
+	timeout time.Duration
+	fn      func() error
+}
+
+// Lifecycle handles the `ready`, `window-all-closed`, `activate`,
+// `before-quit` and `will-quit` app events in one place.
+type Lifecycle struct {
+	// Policy applies on `window-all-closed`
+	Policy QuitPolicy
+	// OnActivate is called on the macOS `activate` event, typically to
+	// create a window again when hasVisibleWindows is false
+	OnActivate func(hasVisibleWindows bool)
+	// OnError is called with the errors of the shutdown hooks, they do not
+	// stop the quit. They are dropped when nil.
+	OnError func(err error)
+
+	app       *AppModule
+	ready     chan struct{}
+	readyOnce sync.Once
+	quitting  bool
+	hooks     [2][]*shutdownHook
+	ran       [2]bool
+}
+
+// NewLifecycleEx creates the lifecycle helper of the main process, it must be
+// called synchronously at startup so that no app event is missed
+func NewLifecycleEx() *Lifecycle {
+	l := &Lifecycle{
+		app:   GetApp(),
+		ready: make(chan struct{}),
+	}
+	if l.app.IsReady() {
+		l.setReady()
+	}
+	l.app.On(EvtAppReady, func(args ...*js.Object) {
+		l.setReady()
+	})
+	l.app.On(EvtAppWindowAllClosed, func(args ...*js.Object) {
+		l.windowAllClosed()
+	})
+	l.app.On(EvtAppActivate, func(args ...*js.Object) {
+		// args: event, hasVisibleWindows
+		if l.OnActivate != nil {
+			l.OnActivate(len(args) > 1 && args[1].Bool())
+		}
+	})
+	l.app.On(EvtAppBeforeQuit, func(args ...*js.Object) {
+		l.quitting = true
+		if !l.shutdown(PhaseBeforeQuit, args[0]) {
+			l.watchQuit(args[0])
+		}
+	})
+	l.app.On(EvtAppWillQuit, func(args ...*js.Object) {
+		if !l.shutdown(PhaseWillQuit, args[0]) {
+			l.cancelIfPrevented(args[0])
+		}
+	})
+	return l
+}
+
+func (l *Lifecycle) setReady() {
+	l.readyOnce.Do(func() {
+		close(l.ready)
+	})
+}
+
+// WaitReady blocks until the app is ready, it returns at once when it
+// already is. It must not be called from an event callback, use a goroutine.
+func (l *Lifecycle) WaitReady() {
+	<-l.ready
+}
+
+// Ready returns a channel closed when the app is ready
+func (l *Lifecycle) Ready() <-chan struct{} {
+	return l.ready
+}
+
+// Quitting reports whether the app is quitting, i.e. `before-quit` was
+// emitted and the quit was not cancelled since. A quit is cancelled when
+// `before-quit` or `will-quit` is prevented, or when a window prevents its
+// `close`. The `beforeunload` of a page is not reported by electron, the
+// app keeps quitting then.
+func (l *Lifecycle) Quitting() bool {
+	return l.quitting
+}
+
+// Quit starts quitting the app, running the shutdown hooks
+func (l *Lifecycle) Quit() {
+	l.app.Quit()
+}
+
+func (l *Lifecycle) windowAllClosed() {
+	switch l.Policy {
+	case KeepRunning:
+	case QuitOnLastWindowExceptDarwin:
+		if js.Global.Get("process").Get("platform").String() != "darwin" {
+			l.Quit()
+		}
+	default:
+		l.Quit()
+	}
+}
+
+// watchQuit cancels the quit when event or the `close` of a window is
+// prevented
+func (l *Lifecycle) watchQuit(event *js.Object) {
+	l.cancelIfPrevented(event)
+	windows := GetAllWindows()
+	for i := 0; i < windows.Length(); i++ {
+		windows.Index(i).Call("once", EvtBrowserWindowClose, func(ev *js.Object) {
+			l.cancelIfPrevented(ev)
+		})
+	}
+}
+
+// cancelIfPrevented cancels the quit when event is prevented, once all its
+// listeners have run. The shutdown hooks run again on the next quit.
+func (l *Lifecycle) cancelIfPrevented(event *js.Object) {
+	js.Global.Call("setImmediate", func() {
+		if event.Get("defaultPrevented").Bool() {
+			l.quitting = false
+			l.ran = [2]bool{}
+		}
+	})
+}
+
+// HideOnClose hides bw instead of closing it, unless the app is quitting.
+// It is meant for the KeepRunning policy, where windows are shown again
+// from the tray.
+func (l *Lifecycle) HideOnClose(bw *BrowserWindow) {
+	bw.On(EvtBrowserWindowClose, func(args ...*js.Object) {
+		if !l.quitting {
+			args[0].Call("preventDefault")
+			bw.Hide()
+		}
+	})
+}
+
+// AddShutdownHook registers fn to run during phase. Hooks of a phase run one
+// after the other by increasing order, then by registration order, and the
+// quit goes on once they are done. A hook taking longer than timeout, when
+// positive, is reported as a *ShutdownTimeoutError and left behind.
+func (l *Lifecycle) AddShutdownHook(phase ShutdownPhase, name string, order int, timeout time.Duration, fn func() error) {
+	h := &shutdownHook{name: name, order: order, timeout: timeout, fn: fn}
+	list := l.hooks[phase]
+	i := len(list)
+	for i > 0 && list[i-1].order > order {
+		i--
+	}
+	list = append(list, nil)
+	copy(list[i+1:], list[i:])
+	list[i] = h
+	l.hooks[phase] = list
+}
+
+// shutdown delays the quit until the hooks of phase have run, then quits
+// again, electron emits the events again and they go through this time. It
+// reports whether the quit was delayed.
+func (l *Lifecycle) shutdown(phase ShutdownPhase, event *js.Object) bool {
+	if l.ran[phase] || len(l.hooks[phase]) == 0 {
+		return false
+	}
+	l.ran[phase] = true
+	event.Call("preventDefault")
+	hooks := l.hooks[phase]
+	go func() {
+		for _, h := range hooks {
+			if err := h.run(); err != nil && l.OnError != nil {
+				l.OnError(err)
+			}
+		}
+		l.Quit()
+	}()
+	return true
+}
+
+func (h *shutdownHook) run() error {
+	if h.timeout <= 0 {
+		return h.wrap(h.fn())
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- h.fn()
+	}()
+	select {
+	case err := <-done:
+		return h.wrap(err)
+	case <-time.After(h.timeout):
+		return &ShutdownTimeoutError{Name: h.name, Timeout: h.timeout}
+	}
+}
+
+func (h *shutdownHook) wrap(err error) error {
+	if err != nil {
+		return fmt.Errorf("electron: shutdown hook %q: %v", h.name, err)
+	}
+	return nil
+}
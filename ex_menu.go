@@ -13,10 +13,31 @@ import (
 // properties of the constructed menu items.
 //
 // !!! You can use the go style struct literal here
+//
+// In renderer processes the menu is built through `electron.remote` and
+// tracked as a RemoteHandle, its items are released when the renderer
+// unloads since their click functions do not exist anymore. Use
+// BuildFromTemplateHandleEx to release it earlier.
 func BuildFromTemplateEx(opts []MenuItemOptionEx) *Menu {
+	m, _ := BuildFromTemplateHandleEx(opts)
+	return m
+}
+
+// BuildFromTemplateHandleEx is BuildFromTemplateEx also returning the
+// RemoteHandle of the menu, nil in the main process. Releasing the handle
+// disables the items and drops their renderer click functions.
+func BuildFromTemplateHandleEx(opts []MenuItemOptionEx) (*Menu, *RemoteHandle) {
 	o := make(js.S, 0)
 	for _, opt := range opts {
 		o = append(o, opt.toMap())
 	}
-	return WrapMenu(electron.Get("Menu").Call("buildFromTemplate", o))
+	m := Get("Menu").Call("buildFromTemplate", o)
+	var h *RemoteHandle
+	if rendererProcess {
+		h = TrackRemoteEx(m, "Menu.buildFromTemplate")
+		h.OnRelease(func() {
+			releaseMenuItems(m)
+		})
+	}
+	return WrapMenu(m), h
 }
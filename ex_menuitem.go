@@ -16,25 +16,31 @@ const (
 	RoleMinimize = "minimize"
 	// close - Close current window
 	RoleClose = "close"
-	// quit- Quit the application
-	RoleQuit = "quit-"
+	// quit - Quit the application
+	RoleQuit = "quit"
 	// reload - Reload the current window
 	RoleReload = "reload"
+	// forcereload - Reload the current window ignoring the cache, electron >= 1.6
+	RoleForcereload = "forcereload"
 	// toggledevtools - Toggle developer tools in the current window
 	RoleToggledevtools = "toggledevtools"
-	// togglefullscreen- Toggle full screen mode on the current window
-	RoleTogglefullscreen = "togglefullscreen-"
+	// togglefullscreen - Toggle full screen mode on the current window
+	RoleTogglefullscreen = "togglefullscreen"
 	// resetzoom - Reset the focused page’s zoom level to the original size
 	RoleResetzoom = "resetzoom"
 	// zoomin - Zoom in the focused page by 10%
 	RoleZoomin = "zoomin"
 	// zoomout - Zoom out the focused page by 10%
 	RoleZoomout = "zoomout"
+	// editMenu - Whole default "Edit" menu (Undo, Copy, etc.), electron >= 1.6
+	RoleEditMenu = "editMenu"
+	// windowMenu - Whole default "Window" menu (Minimize, Close, etc.), electron >= 1.6
+	RoleWindowMenu = "windowMenu"
 
 	// On macOS role can also have following additional values:
 	// When specifying role on macOS, label and accelerator are the only options that
 	// will affect the MenuItem. All other options will be ignored.
-	RoleCOS = "cOS"
+
 	// about - Map to the orderFrontStandardAboutPanel action
 	RoleAbout = "about"
 	// hide - Map to the hide action
@@ -56,9 +62,46 @@ const (
 	// help - The submenu is a “Help” menu
 	RoleHelp = "help"
 	// services - The submenu is a “Services” menu
-	RoleServices = "services "
+	RoleServices = "services"
+)
+
+// validRoles lists the roles documented for electron 1.4 and 1.6, the api
+// files do not carry them as possible values
+var validRoles = map[string]bool{
+	RoleUndo: true, RoleRedo: true, RoleCut: true, RoleCopy: true, RolePaste: true,
+	RolePasteandmatchstyle: true, RoleSelectall: true, RoleDelete: true,
+	RoleMinimize: true, RoleClose: true, RoleQuit: true, RoleReload: true,
+	RoleForcereload: true, RoleToggledevtools: true, RoleTogglefullscreen: true,
+	RoleResetzoom: true, RoleZoomin: true, RoleZoomout: true,
+	RoleEditMenu: true, RoleWindowMenu: true,
+	RoleAbout: true, RoleHide: true, RoleHideothers: true, RoleUnhide: true,
+	RoleStartspeaking: true, RoleStopspeaking: true, RoleFront: true, RoleZoom: true,
+	RoleWindow: true, RoleHelp: true, RoleServices: true,
+}
+
+// IsValidRole reports whether role is a MenuItem role known by electron
+func IsValidRole(role string) bool {
+	return validRoles[role]
+}
+
+// MenuItem types, same values as MenuItemOptionsType
+const (
+	TypeNormal    = string(MenuItemOptionsTypeNormal)
+	TypeSeparator = string(MenuItemOptionsTypeSeparator)
+	TypeSubmenu   = string(MenuItemOptionsTypeSubmenu)
+	TypeCheckbox  = string(MenuItemOptionsTypeCheckbox)
+	TypeRadio     = string(MenuItemOptionsTypeRadio)
 )
 
+// IsValidType reports whether typ is one of the MenuItem types
+func IsValidType(typ string) bool {
+	switch typ {
+	case TypeNormal, TypeSeparator, TypeSubmenu, TypeCheckbox, TypeRadio:
+		return true
+	}
+	return false
+}
+
 type MenuItemOptionEx struct {
 	// *js.Object
 	// click Function (optional) - Will be called with click(menuItem, browserWindow, event)
@@ -79,6 +122,7 @@ type MenuItemOptionEx struct {
 	// sublabel String - (optional)
 	Sublabel string
 	// accelerator Accelerator (optional)
+	Accelerator Accelerator
 	// icon (NativeImage | String) (optional)
 	Icon *NativeImage
 	// enabled Boolean (optional) - If false, the menu item will be greyed out and unclickable.
@@ -121,6 +165,9 @@ func (o *MenuItemOptionEx) toMap() js.M {
 	if o.Sublabel != "" {
 		m["sublabel"] = o.Sublabel
 	}
+	if !o.Accelerator.IsZero() {
+		m["accelerator"] = o.Accelerator.String()
+	}
 	if o.Icon != nil {
 		m["icon"] = o.Icon
 	}
@@ -152,8 +199,9 @@ func (o *MenuItemOptionEx) toMap() js.M {
 	return m
 }
 
+// NewItemEx creates a MenuItem with `new MenuItem(options)`
 func NewItemEx(opt MenuItemOptionEx) *MenuItem {
-	o := electron.Get("Menu").Call("MenuItem", opt.toMap())
+	o := Get("MenuItem").New(opt.toMap())
 	return &MenuItem{
 		Object: o,
 	}
@@ -0,0 +1,223 @@
+package electron
+
+import "github.com/gopherjs/gopherjs/js"
+
+// ModelItem is a menu item of a MenuModel whose state is bound to go values
+type ModelItem struct {
+	// Options are the static options of the item, Click and ClickEx are
+	// ignored, use OnClick instead
+	Options MenuItemOptionEx
+	// LabelFunc returns the label, overriding Options.Label
+	LabelFunc func() string
+	// EnabledFunc returns whether the item is enabled, enabled when nil
+	EnabledFunc func() bool
+	// VisibleFunc returns whether the item is visible, visible when nil
+	VisibleFunc func() bool
+	// Checked binds the checked state of a checkbox or radio item, it is
+	// updated when the user clicks the item
+	Checked *bool
+	// OnClick is called when the item is clicked, after Checked is updated
+	OnClick func()
+	// Submenu items
+	Submenu []*ModelItem
+
+	model    *MenuModel
+	siblings []*ModelItem // the list holding the item, for radio groups
+	native   *js.Object
+	applied  modelItemState
+	click    func(args ...*js.Object)
+}
+
+type modelItemState struct {
+	label   string
+	enabled bool
+	visible bool
+	checked bool
+}
+
+func (it *ModelItem) state() modelItemState {
+	s := modelItemState{
+		label:   it.Options.Label,
+		enabled: true,
+		visible: true,
+	}
+	if it.LabelFunc != nil {
+		s.label = it.LabelFunc()
+	}
+	if it.EnabledFunc != nil {
+		s.enabled = it.EnabledFunc()
+	}
+	if it.VisibleFunc != nil {
+		s.visible = it.VisibleFunc()
+	}
+	if it.Checked != nil {
+		s.checked = *it.Checked
+	}
+	return s
+}
+
+// MenuModel is a go side menu, Update patches the native Menu in place when
+// only the enabled, visible or checked states changed, and rebuilds it when
+// labels changed since electron does not refresh them.
+type MenuModel struct {
+	Items     []*ModelItem
+	menu      *Menu
+	handle    *RemoteHandle // renderer processes only
+	onRebuild func(menu *Menu)
+}
+
+// NewMenuModelEx creates a MenuModel, the native menu is built by the first
+// call to Menu or Update
+func NewMenuModelEx(items ...*ModelItem) *MenuModel {
+	return &MenuModel{Items: items}
+}
+
+// OnRebuild sets fn to be called with every newly built native menu,
+// typically to attach it with SetApplicationMenu or BrowserWindow.SetMenu
+func (m *MenuModel) OnRebuild(fn func(menu *Menu)) {
+	m.onRebuild = fn
+}
+
+// Menu returns the native menu, building it if needed
+func (m *MenuModel) Menu() *Menu {
+	if m.menu == nil {
+		m.Rebuild()
+	}
+	return m.menu
+}
+
+func (m *MenuModel) template(items []*ModelItem) js.S {
+	t := make(js.S, 0, len(items))
+	for _, it := range items {
+		it.model = m
+		it.siblings = items
+		if it.click == nil {
+			item := it
+			// created once so rebuilding does not leak closures
+			it.click = func(args ...*js.Object) {
+				if item.Options.Type == TypeRadio {
+					// electron unchecked the other items of the group
+					for _, x := range item.radioGroup() {
+						x.syncChecked()
+					}
+				} else {
+					item.syncChecked()
+				}
+				if item.OnClick != nil {
+					item.OnClick()
+				}
+				item.model.Update()
+			}
+		}
+		opt := it.Options
+		opt.Click = nil
+		opt.ClickEx = nil
+		opt.SubMenuOptions = nil
+		o := opt.toMap()
+		s := it.state()
+		o["label"] = s.label
+		o["enabled"] = s.enabled
+		o["visible"] = s.visible
+		if it.Checked != nil {
+			o["checked"] = s.checked
+		}
+		o["click"] = it.click
+		if len(it.Submenu) > 0 {
+			o["submenu"] = m.template(it.Submenu)
+		}
+		it.applied = s
+		t = append(t, o)
+	}
+	return t
+}
+
+// syncChecked copies the native checked state into Checked
+func (it *ModelItem) syncChecked() {
+	if it.Checked != nil && it.native != nil {
+		*it.Checked = it.native.Get("checked").Bool()
+	}
+}
+
+// radioGroup returns the consecutive radio items around it, the group
+// electron toggles together
+func (it *ModelItem) radioGroup() []*ModelItem {
+	i := 0
+	for i < len(it.siblings) && it.siblings[i] != it {
+		i++
+	}
+	start, end := i, i
+	for start > 0 && it.siblings[start-1].Options.Type == TypeRadio {
+		start--
+	}
+	for end < len(it.siblings) && it.siblings[end].Options.Type == TypeRadio {
+		end++
+	}
+	return it.siblings[start:end]
+}
+
+// bind links the model items with the native items of menu
+func bindModelItems(items []*ModelItem, menu *js.Object) {
+	natives := menu.Get("items")
+	for i, it := range items {
+		if i >= natives.Length() {
+			return
+		}
+		it.native = natives.Index(i)
+		if len(it.Submenu) > 0 {
+			bindModelItems(it.Submenu, it.native.Get("submenu"))
+		}
+	}
+}
+
+// Rebuild builds a new native menu from the model, needed after items are
+// added or removed
+func (m *MenuModel) Rebuild() {
+	o := Get("Menu").Call("buildFromTemplate", m.template(m.Items))
+	bindModelItems(m.Items, o)
+	if m.handle != nil {
+		m.handle.Release()
+		m.handle = nil
+	}
+	if rendererProcess {
+		m.handle = TrackRemoteEx(o, "MenuModel")
+		m.handle.OnRelease(func() {
+			releaseMenuItems(o)
+		})
+	}
+	m.menu = WrapMenu(o)
+	if m.onRebuild != nil {
+		m.onRebuild(m.menu)
+	}
+}
+
+// patch updates the native items in place and reports whether a rebuild is needed
+func patchModelItems(items []*ModelItem) (rebuild bool) {
+	for _, it := range items {
+		s := it.state()
+		if it.native == nil || s.label != it.applied.label {
+			return true
+		}
+		if s.enabled != it.applied.enabled {
+			it.native.Set("enabled", s.enabled)
+		}
+		if s.visible != it.applied.visible {
+			it.native.Set("visible", s.visible)
+		}
+		if it.Checked != nil && s.checked != it.native.Get("checked").Bool() {
+			it.native.Set("checked", s.checked)
+		}
+		it.applied = s
+		if patchModelItems(it.Submenu) {
+			return true
+		}
+	}
+	return false
+}
+
+// Update refreshes the native menu from the bound state, it is called
+// automatically after every click on a model item.
+func (m *MenuModel) Update() {
+	if m.menu == nil || patchModelItems(m.Items) {
+		m.Rebuild()
+	}
+}
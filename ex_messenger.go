@@ -0,0 +1,154 @@
+package electron
+
+import (
+	"fmt"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// Messenger tracks every live WebContents in the main process and sends go
+// values to all of them, to a single window or to a tagged group of windows.
+//
+// Values are converted through JSON, so renderers receive plain javascript
+// objects exactly as if `webContents.send(channel, value)` was called.
+type Messenger struct {
+	contents map[int64]*messengerTarget // keyed by WebContents.Id
+	groups   map[string]map[int64]bool  // tag -> set of window ids
+}
+
+type messengerTarget struct {
+	wc       *WebContents
+	windowID int64 // 0 when not resolved yet or not owned by a BrowserWindow
+}
+
+// NewMessengerEx creates a Messenger which follows `web-contents-created` and
+// `destroyed` events, existing WebContents are registered immediately.
+// It must be called in the main process.
+func NewMessengerEx() *Messenger {
+	m := &Messenger{
+		contents: make(map[int64]*messengerTarget),
+		groups:   make(map[string]map[int64]bool),
+	}
+	all := GetWebContentsModule().GetAllWebContents()
+	for i := 0; i < all.Length(); i++ {
+		m.track(WrapWebContents(all.Index(i)))
+	}
+	GetApp().On(EvtAppWebContentsCreated, func(args ...*js.Object) {
+		// args: event, webContents
+		if len(args) > 1 {
+			m.track(WrapWebContents(args[1]))
+		}
+	})
+	return m
+}
+
+func (m *Messenger) track(wc *WebContents) {
+	id := wc.Id
+	if _, ok := m.contents[id]; ok {
+		return
+	}
+	t := &messengerTarget{wc: wc}
+	m.contents[id] = t
+	wc.On(EvtWebContentsDestroyed, func(args ...*js.Object) {
+		delete(m.contents, id)
+		if t.windowID != 0 && m.windowContents(t.windowID) == nil {
+			for _, ids := range m.groups {
+				delete(ids, t.windowID)
+			}
+		}
+	})
+}
+
+// resolve returns the id of the BrowserWindow owning t, or 0
+func (t *messengerTarget) resolve() int64 {
+	if t.windowID == 0 {
+		bw := FromWebContents(t.wc)
+		if !isNullish(bw) {
+			t.windowID = WrapBrowserWindow(bw).Id
+		}
+	}
+	return t.windowID
+}
+
+func (m *Messenger) windowContents(windowID int64) *messengerTarget {
+	for _, t := range m.contents {
+		if t.resolve() == windowID {
+			return t
+		}
+	}
+	return nil
+}
+
+// Tag adds the window with windowID to the groups named by tags
+func (m *Messenger) Tag(windowID int64, tags ...string) {
+	for _, tag := range tags {
+		ids, ok := m.groups[tag]
+		if !ok {
+			ids = make(map[int64]bool)
+			m.groups[tag] = ids
+		}
+		ids[windowID] = true
+	}
+}
+
+// Untag removes the window with windowID from the groups named by tags
+func (m *Messenger) Untag(windowID int64, tags ...string) {
+	for _, tag := range tags {
+		delete(m.groups[tag], windowID)
+	}
+}
+
+// Count returns the number of live renderers currently tracked
+func (m *Messenger) Count() int {
+	return len(m.contents)
+}
+
+// Broadcast sends v on channel to every live renderer and returns how many
+// renderers the message was delivered to.
+func (m *Messenger) Broadcast(channel string, v interface{}) (int, error) {
+	return m.sendIf(channel, v, func(t *messengerTarget) bool {
+		return true
+	})
+}
+
+// SendTo sends v on channel to the window with windowID
+func (m *Messenger) SendTo(windowID int64, channel string, v interface{}) (int, error) {
+	n, err := m.sendIf(channel, v, func(t *messengerTarget) bool {
+		return t.resolve() == windowID
+	})
+	if err == nil && n == 0 {
+		err = fmt.Errorf("electron: no live window with id %d", windowID)
+	}
+	return n, err
+}
+
+// SendToGroup sends v on channel to every window tagged with tag
+func (m *Messenger) SendToGroup(tag string, channel string, v interface{}) (int, error) {
+	ids := m.groups[tag]
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	return m.sendIf(channel, v, func(t *messengerTarget) bool {
+		return ids[t.resolve()]
+	})
+}
+
+func (m *Messenger) sendIf(channel string, v interface{}, match func(t *messengerTarget) bool) (int, error) {
+	o, err := toJsValue(v)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for id, t := range m.contents {
+		if t.wc.IsDestroyed() {
+			delete(m.contents, id)
+			continue
+		}
+		if !match(t) {
+			continue
+		}
+		t.wc.Call("send", channel, o)
+		n++
+	}
+	return n, nil
+}
@@ -0,0 +1,206 @@
+package electron
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// will-attach-webview is missing from the bundled api files
+const evtWebContentsWillAttachWebview = "will-attach-webview"
+
+// NavigationDecision is what a NavigationGuard did with a navigation
+type NavigationDecision int
+
+// navigation decisions
+const (
+	NavAllowed NavigationDecision = iota
+	NavBlocked
+	NavOpenedExternally
+)
+
+func (d NavigationDecision) String() string {
+	switch d {
+	case NavAllowed:
+		return "allowed"
+	case NavBlocked:
+		return "blocked"
+	case NavOpenedExternally:
+		return "opened externally"
+	}
+	return fmt.Sprintf("NavigationDecision(%d)", int(d))
+}
+
+// NavigationEvent is a decision of a NavigationGuard
+type NavigationEvent struct {
+	Event         string // will-navigate, new-window or will-attach-webview
+	URL           string
+	WebContentsID int64
+	Decision      NavigationDecision
+	Reason        string
+}
+
+func (e *NavigationEvent) String() string {
+	return fmt.Sprintf("electron: %s %s from webContents %d: %s, %s", e.Event, e.URL, e.WebContentsID, e.Decision, e.Reason)
+}
+
+// NavigationGuard keeps every WebContents on the allowed origins: it
+// prevents the navigations, new windows and webviews to other origins, and
+// can open them in the default browser instead.
+type NavigationGuard struct {
+	// OpenExternal opens the blocked urls with `shell.openExternal` when
+	// their scheme is one of ExternalSchemes
+	OpenExternal bool
+	// ExternalSchemes default to http, https and mailto
+	ExternalSchemes []string
+	// Log receives every decision, defaults to log.Println. Set it to nil
+	// to drop them, or to filter e.g. the allowed ones.
+	Log func(e *NavigationEvent)
+
+	origins map[string]bool
+	guarded map[int64]bool
+}
+
+// NewNavigationGuardEx guards the existing and future WebContents, origins
+// are like "https://example.com", "app://ui" or "file://". It must be
+// called in the main process.
+func NewNavigationGuardEx(origins ...string) *NavigationGuard {
+	g := &NavigationGuard{
+		ExternalSchemes: []string{"http", "https", "mailto"},
+		Log: func(e *NavigationEvent) {
+			log.Println(e)
+		},
+		origins: make(map[string]bool),
+		guarded: make(map[int64]bool),
+	}
+	g.Allow(origins...)
+	all := GetWebContentsModule().GetAllWebContents()
+	for i := 0; i < all.Length(); i++ {
+		g.guard(WrapWebContents(all.Index(i)))
+	}
+	GetApp().On(EvtAppWebContentsCreated, func(args ...*js.Object) {
+		// args: event, webContents
+		if len(args) > 1 {
+			g.guard(WrapWebContents(args[1]))
+		}
+	})
+	return g
+}
+
+// Allow adds origins to the allowlist, invalid ones are ignored
+func (g *NavigationGuard) Allow(origins ...string) {
+	for _, o := range origins {
+		if u, err := url.Parse(o); err == nil {
+			g.origins[urlOrigin(u)] = true
+		}
+	}
+}
+
+// urlOrigin returns scheme://host[:port] in lower case, the host is empty
+// for file urls
+func urlOrigin(u *url.URL) string {
+	scheme := strings.ToLower(u.Scheme)
+	if scheme == "file" {
+		return "file://"
+	}
+	return scheme + "://" + strings.ToLower(u.Host)
+}
+
+// Allowed reports whether rawurl is on an allowed origin
+func (g *NavigationGuard) Allowed(rawurl string) bool {
+	u, err := url.Parse(rawurl)
+	return err == nil && u.Scheme != "" && g.origins[urlOrigin(u)]
+}
+
+func (g *NavigationGuard) guard(wc *WebContents) {
+	id := wc.Id
+	if g.guarded[id] {
+		return
+	}
+	g.guarded[id] = true
+	wc.On(EvtWebContentsWillNavigate, func(args ...*js.Object) {
+		// args: event, url
+		g.check(EvtWebContentsWillNavigate, id, args[0], args[1].String())
+	})
+	wc.On(EvtWebContentsNewWindow, func(args ...*js.Object) {
+		// args: event, url, frameName, disposition, options
+		g.check(EvtWebContentsNewWindow, id, args[0], args[1].String())
+	})
+	wc.On(evtWebContentsWillAttachWebview, func(args ...*js.Object) {
+		// args: event, webPreferences, params
+		g.attachWebview(id, args[0], args[1], args[2])
+	})
+	wc.On(EvtWebContentsDestroyed, func(args ...*js.Object) {
+		delete(g.guarded, id)
+	})
+}
+
+func (g *NavigationGuard) check(event string, id int64, ev *js.Object, rawurl string) {
+	e := &NavigationEvent{Event: event, URL: rawurl, WebContentsID: id}
+	defer g.log(e)
+	if g.Allowed(rawurl) {
+		e.Decision, e.Reason = NavAllowed, "allowed origin"
+		return
+	}
+	ev.Call("preventDefault")
+	e.Decision, e.Reason = NavBlocked, "origin not allowed"
+	if !g.OpenExternal {
+		return
+	}
+	if err := g.validExternal(rawurl); err != nil {
+		e.Reason = err.Error()
+		return
+	}
+	GetShellModule().Call("openExternal", rawurl)
+	e.Decision, e.Reason = NavOpenedExternally, "external scheme"
+}
+
+// validExternal checks that rawurl can be given to the default browser
+func (g *NavigationGuard) validExternal(rawurl string) error {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return fmt.Errorf("invalid url: %v", err)
+	}
+	ok := false
+	for _, s := range g.ExternalSchemes {
+		if strings.EqualFold(s, u.Scheme) {
+			ok = true
+			break
+		}
+	}
+	switch {
+	case !ok:
+		return fmt.Errorf("scheme %q not allowed externally", u.Scheme)
+	case u.User != nil:
+		return fmt.Errorf("credentials in url")
+	case (u.Scheme == "http" || u.Scheme == "https") && u.Host == "":
+		return fmt.Errorf("no host")
+	}
+	return nil
+}
+
+// attachWebview blocks the webviews of other origins, and removes the
+// preload scripts and node integration of the allowed ones
+func (g *NavigationGuard) attachWebview(id int64, ev, prefs, params *js.Object) {
+	src := jsString(params.Get("src"))
+	e := &NavigationEvent{Event: evtWebContentsWillAttachWebview, URL: src, WebContentsID: id}
+	defer g.log(e)
+	if !g.Allowed(src) {
+		ev.Call("preventDefault")
+		e.Decision, e.Reason = NavBlocked, "origin not allowed"
+		return
+	}
+	prefs.Delete("preload")
+	prefs.Delete("preloadURL")
+	prefs.Set("nodeIntegration", false)
+	e.Decision, e.Reason = NavAllowed, "allowed origin, preload and node integration removed"
+}
+
+func (g *NavigationGuard) log(e *NavigationEvent) {
+	if g.Log != nil {
+		g.Log(e)
+	}
+}
@@ -0,0 +1,233 @@
+package electron
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// permissions requested through `setPermissionRequestHandler`
+const (
+	PermissionMedia         = "media"
+	PermissionGeolocation   = "geolocation"
+	PermissionNotifications = "notifications"
+	PermissionMidiSysex     = "midiSysex"
+	PermissionPointerLock   = "pointerLock"
+	PermissionFullscreen    = "fullscreen"
+	PermissionOpenExternal  = "openExternal"
+)
+
+// PermissionAction is what a PermissionPolicy does with a request
+type PermissionAction int
+
+// permission actions, the zero value denies
+const (
+	PermissionDeny PermissionAction = iota
+	PermissionAllow
+	PermissionAsk
+)
+
+// PermissionPolicy grants or denies the permission requests of a session
+// by origin and permission. Requests to ask about show a message box, and
+// the answer is saved into `<userData>/permissions-<name>.json`.
+//
+// The bundled electron versions only give the requesting WebContents, the
+// origin is then the one of its top level page and the frames of other
+// origins it embeds share its rules and answers. The origin of the
+// requesting frame is used on the versions giving it.
+type PermissionPolicy struct {
+	// Default applies to the requests no rule matches, PermissionDeny by
+	// default
+	Default PermissionAction
+	// Log receives every decision, it is not called when nil
+	Log func(origin, permission string, granted bool, reason string)
+	// OnError is called when loading or saving the answers fails, defaults
+	// to log.Println. The errors are dropped when nil.
+	OnError func(err error)
+
+	rules     map[string]map[string]PermissionAction // origin, then permission
+	decisions map[string]map[string]bool             // remembered answers
+	pending   map[string][]func(bool)
+	path      string
+	loaded    bool
+}
+
+// NewPermissionPolicyEx creates a policy whose answers are saved under name,
+// e.g. the session partition, escaped into a valid file name. It must be
+// called in the main process. The saved answers are loaded on first use.
+func NewPermissionPolicyEx(name string) *PermissionPolicy {
+	return &PermissionPolicy{
+		OnError: func(err error) {
+			log.Println(err)
+		},
+		rules:     make(map[string]map[string]PermissionAction),
+		decisions: make(map[string]map[string]bool),
+		pending:   make(map[string][]func(bool)),
+		path:      userDataPath("permissions-" + escapeFileName(name) + ".json"),
+	}
+}
+
+// escapeFileName escapes the bytes of name which are not letters, digits,
+// dots, dashes or underscores as %XX, e.g. the colon of "persist:foo"
+// which is invalid on windows
+func escapeFileName(name string) string {
+	ret := ""
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '-' || c == '_' {
+			ret += string(c)
+		} else {
+			ret += fmt.Sprintf("%%%02X", c)
+		}
+	}
+	return ret
+}
+
+// load reads the saved answers once, a missing file is no answer
+func (p *PermissionPolicy) load() {
+	if p.loaded {
+		return
+	}
+	p.loaded = true
+	decisions := make(map[string]map[string]bool)
+	err := readJSONFile(p.path, &decisions)
+	switch {
+	case err == nil:
+		p.decisions = decisions
+	case !isNotExist(err) && p.OnError != nil:
+		p.OnError(fmt.Errorf("electron: loading permissions %s: %v", p.path, err))
+	}
+}
+
+func (p *PermissionPolicy) save() {
+	if err := writeJSONFile(p.path, p.decisions); err != nil && p.OnError != nil {
+		p.OnError(fmt.Errorf("electron: saving permissions %s: %v", p.path, err))
+	}
+}
+
+// Set sets the action for permission requested by origin, like
+// "https://example.com" or "file://". Both can be "*" to match any, the
+// most specific rule applies: origin and permission, then origin, then
+// permission, then "*" for both.
+func (p *PermissionPolicy) Set(origin, permission string, action PermissionAction) *PermissionPolicy {
+	if origin != "*" {
+		if u, err := url.Parse(origin); err == nil {
+			origin = urlOrigin(u)
+		}
+	}
+	if p.rules[origin] == nil {
+		p.rules[origin] = make(map[string]PermissionAction)
+	}
+	p.rules[origin][permission] = action
+	return p
+}
+
+// Action returns the action applying to permission requested by origin
+func (p *PermissionPolicy) Action(origin, permission string) PermissionAction {
+	for _, k := range [][2]string{{origin, permission}, {origin, "*"}, {"*", permission}, {"*", "*"}} {
+		if a, ok := p.rules[k[0]][k[1]]; ok {
+			return a
+		}
+	}
+	return p.Default
+}
+
+// Forget removes the remembered answer about permission for origin, the
+// user is asked again next time
+func (p *PermissionPolicy) Forget(origin, permission string) error {
+	p.load()
+	delete(p.decisions[origin], permission)
+	if len(p.decisions[origin]) == 0 {
+		delete(p.decisions, origin)
+	}
+	return writeJSONFile(p.path, p.decisions)
+}
+
+// Reset removes every remembered answer
+func (p *PermissionPolicy) Reset() error {
+	p.loaded = true
+	p.decisions = make(map[string]map[string]bool)
+	return writeJSONFile(p.path, p.decisions)
+}
+
+// Apply makes p the permission request handler of s, or of the default
+// session when s is nil. Use `session.fromPartition` for a partition.
+func (p *PermissionPolicy) Apply(s *Session) {
+	if s == nil {
+		s = GetSessionModule().DefaultSession
+	}
+	s.Call("setPermissionRequestHandler", func(args ...*js.Object) {
+		// args: webContents, permission, callback[, details]
+		wc := WrapWebContents(args[0])
+		rawurl := wc.GetURL()
+		if len(args) > 3 && !isNullish(args[3]) && jsString(args[3].Get("requestingUrl")) != "" {
+			rawurl = jsString(args[3].Get("requestingUrl"))
+		}
+		callback := args[2]
+		p.request(wc, rawurl, args[1].String(), func(granted bool) {
+			callback.Invoke(granted)
+		})
+	})
+}
+
+func (p *PermissionPolicy) request(wc *WebContents, rawurl, permission string, done func(bool)) {
+	p.load()
+	origin := ""
+	if u, err := url.Parse(rawurl); err == nil {
+		origin = urlOrigin(u)
+	}
+	decide := func(granted bool, reason string) {
+		if p.Log != nil {
+			p.Log(origin, permission, granted, reason)
+		}
+		done(granted)
+	}
+	switch p.Action(origin, permission) {
+	case PermissionAllow:
+		decide(true, "allowed by policy")
+		return
+	case PermissionDeny:
+		decide(false, "denied by policy")
+		return
+	}
+	if granted, ok := p.decisions[origin][permission]; ok {
+		decide(granted, "remembered answer")
+		return
+	}
+	key := origin + " " + permission
+	p.pending[key] = append(p.pending[key], func(granted bool) {
+		decide(granted, "asked")
+	})
+	if len(p.pending[key]) > 1 {
+		// already asking
+		return
+	}
+	var parent *BrowserWindow
+	if bw := FromWebContents(wc); !isNullish(bw) {
+		parent = WrapBrowserWindow(bw)
+	}
+	opt := DialogOptionMessage{
+		Type:      DialogTypeQuestion,
+		Buttons:   []string{"Allow", "Deny"},
+		DefaultID: 1,
+		CancelID:  1,
+		Title:     "Permission request",
+		Message:   fmt.Sprintf("%s wants to use %s", origin, permission),
+		Detail:    "Your answer will be remembered.",
+	}
+	GetDialogModule().ShowMessageBoxAsyncEx(opt, parent, func(r MessageResult) {
+		granted := r.Button == 0
+		if p.decisions[origin] == nil {
+			p.decisions[origin] = make(map[string]bool)
+		}
+		p.decisions[origin][permission] = granted
+		p.save()
+		waiting := p.pending[key]
+		delete(p.pending, key)
+		for _, fn := range waiting {
+			fn(granted)
+		}
+	})
+}
@@ -0,0 +1,205 @@
+package electron
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// results of the verify proc callback of the electron versions giving a
+// request object
+const (
+	certVerifyTrusted    = 0
+	certVerifyFailed     = -2
+	certVerifyUseDefault = -3
+)
+
+// PinFailure is a certificate rejected by a CertificatePinner
+type PinFailure struct {
+	Hostname string
+	Chain    []*x509.Certificate // leaf first, empty when unparsable
+	Err      error
+}
+
+func (f *PinFailure) Error() string {
+	return fmt.Sprintf("electron: certificate of %s rejected: %v", f.Hostname, f.Err)
+}
+
+// ErrPinMismatch is the error of the chains matching none of the pins
+var ErrPinMismatch = errors.New("no certificate of the chain matches the pins")
+
+// CertificatePinner pins the SPKI hashes of the certificates of some hosts
+// with `setCertificateVerifyProc`. Pinned hosts must pass the usual
+// verification and have a certificate of their chain matching a pin.
+//
+// The hosts which are not pinned are left to chromium when electron gives
+// the verify proc a request object. The older versions, like the bundled
+// 1.4 and 1.6, only give the certificate and trust whatever the proc
+// accepts: every host is then verified in go against Roots. Applying a
+// pinner without pins restores the default verification.
+type CertificatePinner struct {
+	// Roots verify the certificates on the electron versions not giving a
+	// request object, the system pool when nil. The system pool may be
+	// unavailable with gopherjs, every certificate is then rejected and
+	// Roots must be set.
+	Roots *x509.CertPool
+	// OnFailure receives every rejected certificate, it is not called when nil
+	OnFailure func(f *PinFailure)
+
+	pins map[string]map[string]bool
+}
+
+// NewCertificatePinnerEx creates a pinner without pins
+func NewCertificatePinnerEx() *CertificatePinner {
+	return &CertificatePinner{
+		pins: make(map[string]map[string]bool),
+	}
+}
+
+// Pin adds the base64 SHA-256 SPKI hashes for host, as returned by SPKIHash
+// and optionally prefixed by "sha256/". A host "*.example.com" pins the
+// subdomains of example.com, exact hosts take precedence.
+func (p *CertificatePinner) Pin(host string, hashes ...string) *CertificatePinner {
+	host = strings.ToLower(host)
+	if p.pins[host] == nil {
+		p.pins[host] = make(map[string]bool)
+	}
+	for _, h := range hashes {
+		p.pins[host][strings.TrimPrefix(h, "sha256/")] = true
+	}
+	return p
+}
+
+// pinsFor returns the pins of hostname, nil when it is not pinned
+func (p *CertificatePinner) pinsFor(hostname string) map[string]bool {
+	hostname = strings.ToLower(hostname)
+	if pins, ok := p.pins[hostname]; ok {
+		return pins
+	}
+	for i := strings.Index(hostname, "."); i >= 0; i = strings.Index(hostname, ".") {
+		hostname = hostname[i+1:]
+		if pins, ok := p.pins["*."+hostname]; ok {
+			return pins
+		}
+	}
+	return nil
+}
+
+// Apply makes p the verify proc of s, or of the default session when s is
+// nil. The verify proc is removed when p has no pins.
+func (p *CertificatePinner) Apply(s *Session) {
+	if s == nil {
+		s = GetSessionModule().DefaultSession
+	}
+	if len(p.pins) == 0 {
+		s.Call("setCertificateVerifyProc", nil)
+		return
+	}
+	s.Call("setCertificateVerifyProc", func(args ...*js.Object) {
+		if len(args) == 2 {
+			// request, callback
+			args[1].Invoke(p.verifyRequest(args[0]))
+			return
+		}
+		// hostname, certificate, callback, callback(true) accepts any
+		// certificate so every host is verified
+		err := p.Verify(args[0].String(), WrapCertificateEx(args[1]))
+		args[2].Invoke(err == nil)
+	})
+}
+
+func (p *CertificatePinner) verifyRequest(req *js.Object) int {
+	hostname := req.Get("hostname").String()
+	pins := p.pinsFor(hostname)
+	if pins == nil {
+		return certVerifyUseDefault
+	}
+	cert := WrapCertificateEx(req.Get("certificate"))
+	chain, err := cert.Chain()
+	if err == nil && req.Get("errorCode").Int() != 0 {
+		err = fmt.Errorf("chromium verification: %s", req.Get("verificationResult"))
+	}
+	if err == nil {
+		err = matchPins(chain, pins)
+	}
+	if err != nil {
+		p.fail(hostname, chain, err)
+		return certVerifyFailed
+	}
+	return certVerifyTrusted
+}
+
+// Verify verifies cert for hostname against Roots, then against the pins of
+// hostname
+func (p *CertificatePinner) Verify(hostname string, cert *CertificateEx) error {
+	if cert == nil {
+		return p.fail(hostname, nil, errors.New("no certificate"))
+	}
+	chain, err := cert.Chain()
+	if err != nil {
+		return p.fail(hostname, nil, err)
+	}
+	intermediates := x509.NewCertPool()
+	for _, c := range chain[1:] {
+		intermediates.AddCert(c)
+	}
+	_, err = chain[0].Verify(x509.VerifyOptions{
+		DNSName:       hostname,
+		Roots:         p.Roots,
+		Intermediates: intermediates,
+	})
+	if err != nil {
+		return p.fail(hostname, chain, err)
+	}
+	if pins := p.pinsFor(hostname); pins != nil {
+		if err := matchPins(chain, pins); err != nil {
+			return p.fail(hostname, chain, err)
+		}
+	}
+	return nil
+}
+
+func matchPins(chain []*x509.Certificate, pins map[string]bool) error {
+	for _, c := range chain {
+		if pins[SPKIHash(c)] {
+			return nil
+		}
+	}
+	return ErrPinMismatch
+}
+
+func (p *CertificatePinner) fail(hostname string, chain []*x509.Certificate, err error) error {
+	f := &PinFailure{Hostname: hostname, Chain: chain, Err: err}
+	if p.OnFailure != nil {
+		p.OnFailure(f)
+	}
+	return f
+}
+
+// CertificateErrorEx is a `certificate-error` app event
+type CertificateErrorEx struct {
+	WebContents *WebContents
+	URL         string
+	Error       string // e.g. net::ERR_CERT_AUTHORITY_INVALID
+	Certificate *CertificateEx
+}
+
+// HandleCertificateErrorEx calls fn on the `certificate-error` app event,
+// the certificate is trusted when fn returns true and rejected otherwise.
+// It must be called in the main process.
+func HandleCertificateErrorEx(fn func(e *CertificateErrorEx) (trust bool)) {
+	GetApp().On(EvtAppCertificateError, func(args ...*js.Object) {
+		// args: event, webContents, url, error, certificate, callback
+		args[0].Call("preventDefault")
+		e := &CertificateErrorEx{
+			WebContents: WrapWebContents(args[1]),
+			URL:         jsString(args[2]),
+			Error:       jsString(args[3]),
+			Certificate: WrapCertificateEx(args[4]),
+		}
+		args[5].Invoke(fn(e))
+	})
+}
@@ -0,0 +1,188 @@
+package electron
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// bytesFromJs copies a node Buffer or Uint8Array into a []byte
+func bytesFromJs(o *js.Object) []byte {
+	if isNullish(o) {
+		return nil
+	}
+	u8 := js.Global.Get("Uint8Array").New(o.Get("buffer"), o.Get("byteOffset"), o.Get("length"))
+	if b, ok := u8.Interface().([]byte); ok {
+		return append([]byte(nil), b...)
+	}
+	b := make([]byte, u8.Length())
+	for i := range b {
+		b[i] = byte(u8.Index(i).Int())
+	}
+	return b
+}
+
+// bytesToBuffer returns a node Buffer holding a copy of b
+func bytesToBuffer(b []byte) *js.Object {
+	if b == nil {
+		b = []byte{}
+	}
+	return js.Global.Get("Buffer").Call("from", b)
+}
+
+// protocolRequest converts the request object of a protocol handler
+func protocolRequest(o *js.Object) (*http.Request, error) {
+	body := []byte{}
+	if data := o.Get("uploadData"); !isNullish(data) {
+		for i := 0; i < data.Length(); i++ {
+			part := data.Index(i)
+			switch {
+			case !isNullish(part.Get("bytes")):
+				body = append(body, bytesFromJs(part.Get("bytes"))...)
+			case !isNullish(part.Get("file")):
+				var b []byte
+				err := jsTry(func() {
+					b = bytesFromJs(require.Invoke("fs").Call("readFileSync", part.Get("file")))
+				})
+				if err != nil {
+					return nil, err
+				}
+				body = append(body, b...)
+			}
+		}
+	}
+	method := "GET"
+	if m := jsString(o.Get("method")); m != "" {
+		method = m
+	}
+	req, err := http.NewRequest(method, o.Get("url").String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.RequestURI = req.URL.RequestURI()
+	if ref := jsString(o.Get("referrer")); ref != "" {
+		req.Header.Set("Referer", ref)
+	}
+	// only given by the electron versions which have them
+	if headers := o.Get("headers"); !isNullish(headers) {
+		keys := js.Global.Get("Object").Call("keys", headers)
+		for i := 0; i < keys.Length(); i++ {
+			k := keys.Index(i).String()
+			req.Header.Set(k, headers.Get(k).String())
+		}
+	}
+	return req, nil
+}
+
+// protocolResponse records the response of an http.Handler
+type protocolResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newProtocolResponse() *protocolResponse {
+	return &protocolResponse{header: http.Header{}}
+}
+
+func (w *protocolResponse) Header() http.Header {
+	return w.header
+}
+
+func (w *protocolResponse) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *protocolResponse) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	if w.header.Get("Content-Type") == "" {
+		w.header.Set("Content-Type", http.DetectContentType(b))
+	}
+	return w.body.Write(b)
+}
+
+// serve runs h on the request, panics are turned into 500 responses
+func (w *protocolResponse) serve(h http.Handler, req *http.Request) {
+	defer func() {
+		if r := recover(); r != nil {
+			w.header = http.Header{"Content-Type": {"text/plain; charset=utf-8"}}
+			w.status = http.StatusInternalServerError
+			w.body.Reset()
+			fmt.Fprintf(&w.body, "%v", r)
+		}
+	}()
+	h.ServeHTTP(w, req)
+	w.WriteHeader(http.StatusOK)
+}
+
+// streamResponse is the response object of `registerStreamProtocol`
+func (w *protocolResponse) streamResponse() js.M {
+	headers := js.M{}
+	for k, v := range w.header {
+		headers[k] = strings.Join(v, ", ")
+	}
+	stream := require.Invoke("stream").Get("PassThrough").New()
+	stream.Call("end", bytesToBuffer(w.body.Bytes()))
+	return js.M{
+		"statusCode": w.status,
+		"headers":    headers,
+		"data":       stream,
+	}
+}
+
+// bufferResponse is the response object of `registerBufferProtocol`, which
+// has no status nor headers
+func (w *protocolResponse) bufferResponse() js.M {
+	ret := js.M{"data": bytesToBuffer(w.body.Bytes())}
+	if mt, params, err := mime.ParseMediaType(w.header.Get("Content-Type")); err == nil {
+		ret["mimeType"] = mt
+		if cs, ok := params["charset"]; ok {
+			ret["charset"] = cs
+		}
+	}
+	return ret
+}
+
+// protocolHandler adapts h to a protocol handler, stream tells whether the
+// response is for `registerStreamProtocol` or `registerBufferProtocol`
+func protocolHandler(h http.Handler, stream bool) func(req, callback *js.Object) {
+	return func(o, callback *js.Object) {
+		// the handler may block, callback can be called later
+		go func() {
+			w := newProtocolResponse()
+			req, err := protocolRequest(o)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			} else {
+				w.serve(h, req)
+			}
+			if stream {
+				callback.Invoke(w.streamResponse())
+			} else {
+				callback.Invoke(w.bufferResponse())
+			}
+		}()
+	}
+}
+
+// ServeHandler serves the requests of the custom scheme with h, e.g.
+// `app://ui/index.html`, without opening a localhost port.
+//
+// The status and headers are only passed to electron when it provides
+// `protocol.registerStreamProtocol`, otherwise only the body and the mime
+// type are. It must be called after the app is ready, from a goroutine as
+// it waits for the registration.
+func (p *ProtocolModule) ServeHandler(scheme string, h http.Handler) error {
+	stream := !isNullish(p.Get("registerStreamProtocol"))
+	method := "registerBufferProtocol"
+	if stream {
+		method = "registerStreamProtocol"
+	}
+	return p.protocolCall(method, scheme, protocolHandler(h, stream))
+}
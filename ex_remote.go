@@ -0,0 +1,141 @@
+package electron
+
+import (
+	"sort"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// RemoteHandle keeps a main process object got through `electron.remote`
+// together with the renderer callbacks it holds, so that they can be
+// released explicitly instead of waiting for the garbage collector.
+//
+// Every handle is released automatically when the renderer window unloads,
+// this avoids the main process calling functions of a closed or reloaded
+// renderer.
+type RemoteHandle struct {
+	*js.Object
+	ID        int
+	Label     string
+	listeners []remoteListener
+	releasers []func()
+}
+
+type remoteListener struct {
+	evt string
+	fn  func(args ...*js.Object)
+}
+
+// RemoteRef describes an outstanding RemoteHandle, see OutstandingRemoteEx
+type RemoteRef struct {
+	ID        int
+	Label     string
+	Listeners int // event listeners registered through the handle
+}
+
+var (
+	remoteHandles   = make(map[int]*RemoteHandle)
+	remoteLastID    = 0
+	remoteUnloadSet = false
+)
+
+// TrackRemoteEx registers the remote object o under label and returns its
+// handle. Nil or undefined objects are tracked too, Release is then a no-op.
+func TrackRemoteEx(o *js.Object, label string) *RemoteHandle {
+	if rendererProcess && !remoteUnloadSet {
+		remoteUnloadSet = true
+		js.Global.Call("addEventListener", "unload", func() {
+			ReleaseAllRemoteEx()
+		})
+	}
+	remoteLastID++
+	h := &RemoteHandle{
+		Object: o,
+		ID:     remoteLastID,
+		Label:  label,
+	}
+	remoteHandles[h.ID] = h
+	return h
+}
+
+// On adds an event listener to the remote emitter, the listener is removed
+// when the handle is released.
+func (h *RemoteHandle) On(evt string, fn func(args ...*js.Object)) {
+	if h.Released() || isNullish(h.Object) {
+		return
+	}
+	h.Call("on", evt, fn)
+	h.listeners = append(h.listeners, remoteListener{evt: evt, fn: fn})
+}
+
+// OnRelease adds fn to the functions called when the handle is released
+func (h *RemoteHandle) OnRelease(fn func()) {
+	h.releasers = append(h.releasers, fn)
+}
+
+// Released reports whether Release has been called
+func (h *RemoteHandle) Released() bool {
+	_, ok := remoteHandles[h.ID]
+	return !ok
+}
+
+// Release removes the listeners added with On, runs the OnRelease functions
+// and drops the reference to the remote object so that the main process
+// copy can be collected.
+func (h *RemoteHandle) Release() {
+	if h.Released() {
+		return
+	}
+	delete(remoteHandles, h.ID)
+	if !isNullish(h.Object) {
+		for _, l := range h.listeners {
+			h.Call("removeListener", l.evt, l.fn)
+		}
+	}
+	for _, fn := range h.releasers {
+		fn()
+	}
+	h.listeners = nil
+	h.releasers = nil
+	h.Object = nil
+}
+
+// ReleaseAllRemoteEx releases every outstanding handle
+func ReleaseAllRemoteEx() {
+	for _, h := range remoteHandles {
+		h.Release()
+	}
+}
+
+// OutstandingRemoteEx lists the handles not released yet, oldest first
+func OutstandingRemoteEx() []RemoteRef {
+	refs := make([]RemoteRef, 0, len(remoteHandles))
+	for _, h := range remoteHandles {
+		refs = append(refs, RemoteRef{
+			ID:        h.ID,
+			Label:     h.Label,
+			Listeners: len(h.listeners),
+		})
+	}
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].ID < refs[j].ID
+	})
+	return refs
+}
+
+// releaseMenuItems disables every item of the remote menu m, recursively,
+// and drops their click functions. Used to release menus built from a
+// renderer: their click functions live in the renderer and must not be
+// called, nor kept alive by the main process, once it is gone.
+func releaseMenuItems(m *js.Object) {
+	if isNullish(m) {
+		return
+	}
+	items := m.Get("items")
+	for i := 0; i < items.Length(); i++ {
+		item := items.Index(i)
+		item.Set("enabled", false)
+		item.Set("click", nil)
+		releaseMenuItems(item.Get("submenu"))
+	}
+}
@@ -0,0 +1,184 @@
+package electron
+
+import (
+	"encoding/json"
+	"html"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// DefaultCSP only allows the content of the page's own origin, no plugins
+// and no framing
+const DefaultCSP = "default-src 'self'; script-src 'self'; object-src 'none'; base-uri 'self'; form-action 'self'; frame-ancestors 'none'"
+
+// CSPViolation is a Content-Security-Policy violation report
+type CSPViolation struct {
+	DocumentURI        string `json:"document-uri"`
+	Referrer           string `json:"referrer"`
+	ViolatedDirective  string `json:"violated-directive"`
+	EffectiveDirective string `json:"effective-directive"`
+	OriginalPolicy     string `json:"original-policy"`
+	Disposition        string `json:"disposition"`
+	BlockedURI         string `json:"blocked-uri"`
+	StatusCode         int    `json:"status-code"`
+	SourceFile         string `json:"source-file"`
+	LineNumber         int    `json:"line-number"`
+	ColumnNumber       int    `json:"column-number"`
+	ScriptSample       string `json:"script-sample"`
+}
+
+// SecurityOptions configures a SecurityPolicy, the zero value gives the
+// defaults
+type SecurityOptions struct {
+	// CSP is the Content-Security-Policy, default to DefaultCSP
+	CSP string
+	// ReportOnly sends the policy as Content-Security-Policy-Report-Only,
+	// violations are reported but not blocked
+	ReportOnly bool
+	// Headers are added to the defaults: X-Content-Type-Options nosniff,
+	// X-Frame-Options DENY and Referrer-Policy no-referrer
+	Headers http.Header
+	// OnViolation receives the violation reports, they are posted to
+	// `<ReportScheme>://report/`
+	OnViolation func(v *CSPViolation)
+	// ReportScheme defaults to csp-report, it must be registered with
+	// RegisterAssetSchemeEx before the app is ready
+	ReportScheme string
+}
+
+// SecurityPolicy sets the same security headers on every response of a
+// session: the http and https ones and those of custom schemes through a
+// WebRequestPipeline, the file ones by intercepting the file scheme.
+type SecurityPolicy struct {
+	opt    SecurityOptions
+	header http.Header
+}
+
+// NewSecurityPolicyEx creates a policy, opt can be nil
+func NewSecurityPolicyEx(opt *SecurityOptions) *SecurityPolicy {
+	sp := &SecurityPolicy{}
+	if opt != nil {
+		sp.opt = *opt
+	}
+	if sp.opt.CSP == "" {
+		sp.opt.CSP = DefaultCSP
+	}
+	if sp.opt.ReportScheme == "" {
+		sp.opt.ReportScheme = "csp-report"
+	}
+	csp := sp.opt.CSP
+	if sp.opt.OnViolation != nil {
+		csp = strings.TrimRight(csp, "; ") + "; report-uri " + sp.ReportURL()
+	}
+	sp.header = http.Header{
+		"X-Content-Type-Options": {"nosniff"},
+		"X-Frame-Options":        {"DENY"},
+		"Referrer-Policy":        {"no-referrer"},
+	}
+	if sp.opt.ReportOnly {
+		sp.header.Set("Content-Security-Policy-Report-Only", csp)
+	} else {
+		sp.header.Set("Content-Security-Policy", csp)
+	}
+	for k, v := range sp.opt.Headers {
+		sp.header[http.CanonicalHeaderKey(k)] = v
+	}
+	return sp
+}
+
+// ReportURL is the url the violations are reported to
+func (sp *SecurityPolicy) ReportURL() string {
+	return sp.opt.ReportScheme + "://report/"
+}
+
+// Header returns a copy of the headers set by the policy
+func (sp *SecurityPolicy) Header() http.Header {
+	h := http.Header{}
+	for k, v := range sp.header {
+		h[k] = append([]string(nil), v...)
+	}
+	return h
+}
+
+// Enforce applies the policy: it adds a rule to p, intercepts the file
+// scheme and serves the report endpoint when OnViolation is set. Protocols
+// are not per session, the file urls of every session get the policy. It
+// must be called after the app is ready, from a goroutine.
+func (sp *SecurityPolicy) Enforce(p *WebRequestPipeline) error {
+	if _, err := p.Add(ResponseHeaderRule(sp.header, nil)); err != nil {
+		return err
+	}
+	protocol := GetProtocolModule()
+	if _, err := protocol.Intercept("file", securityInterceptOrder, sp.interceptFile); err != nil {
+		return err
+	}
+	if sp.opt.OnViolation != nil {
+		return protocol.ServeHandler(sp.opt.ReportScheme, http.HandlerFunc(sp.serveReport))
+	}
+	return nil
+}
+
+// Handler sets the policy headers on the responses of h, for the content
+// served with ServeHandler or ServeAssetsEx
+func (sp *SecurityPolicy) Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for k, v := range sp.header {
+			w.Header()[k] = v
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// the file middleware of the policy answers every request, it runs after
+// the other middlewares
+const securityInterceptOrder = 1 << 20
+
+var htmlHeadTag = regexp.MustCompile(`(?i)<head(\s[^>]*)?>`)
+
+// interceptFile serves the file urls with the policy headers, the policy is
+// also added as a meta tag to html files as the headers need the stream
+// protocol
+func (sp *SecurityPolicy) interceptFile(req *InterceptRequest) *InterceptResponse {
+	name := fileURLPath(req.URL)
+	ext := strings.ToLower(path.Ext(req.URL.Path))
+	if sp.opt.ReportOnly || (ext != ".html" && ext != ".htm") {
+		return InterceptFile(name).WithHeader(sp.header)
+	}
+	w, code := fileResponse(name)
+	if code != 0 {
+		return InterceptError(code)
+	}
+	// report-uri and frame-ancestors are ignored in meta tags
+	meta := `<meta http-equiv="Content-Security-Policy" content="` + html.EscapeString(sp.opt.CSP) + `">`
+	page := w.body.String()
+	if loc := htmlHeadTag.FindStringIndex(page); loc != nil {
+		page = page[:loc[1]] + meta + page[loc[1]:]
+	} else {
+		page = meta + page
+	}
+	return InterceptString(page, "text/html; charset=utf-8").WithHeader(sp.header)
+}
+
+func (sp *SecurityPolicy) serveReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "405 method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	report := struct {
+		Violation *CSPViolation `json:"csp-report"`
+	}{}
+	if err := json.Unmarshal(b, &report); err != nil || report.Violation == nil {
+		http.Error(w, "invalid csp report", http.StatusBadRequest)
+		return
+	}
+	sp.opt.OnViolation(report.Violation)
+	w.WriteHeader(http.StatusNoContent)
+}
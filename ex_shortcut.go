@@ -0,0 +1,230 @@
+package electron
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// ShortcutConflictError is returned when registering an accelerator already
+// registered through the ShortcutManager
+type ShortcutConflictError struct {
+	Accelerator Accelerator
+	Owner       string // owner of the existing registration
+}
+
+func (e *ShortcutConflictError) Error() string {
+	return fmt.Sprintf("electron: global shortcut %s already registered by %q", e.Accelerator, e.Owner)
+}
+
+// ShortcutManager keeps track of the global shortcuts of the application and
+// of their owners. Shortcuts can be scoped to a window, they are then only
+// registered while the window is focused. Everything is unregistered on the
+// app `will-quit` event.
+type ShortcutManager struct {
+	// OnError is called with the errors happening outside Register calls,
+	// when registering window scoped shortcuts on focus. Defaults to
+	// log.Println, they are dropped when nil.
+	OnError  func(err error)
+	gs       *GlobalShortcutModule
+	platform string
+	entries  map[string][]*shortcutEntry // keyed by platform normalized accelerator
+}
+
+type shortcutEntry struct {
+	owner  string
+	acc    Accelerator
+	fn     func()
+	window int64 // id of the scoping window, 0 for application wide shortcuts
+	active bool
+	detach func() // removes the listeners of the scoping window
+}
+
+// NewShortcutManagerEx creates a ShortcutManager, it must be called in the
+// main process
+func NewShortcutManagerEx() *ShortcutManager {
+	m := &ShortcutManager{
+		OnError: func(err error) {
+			log.Println(err)
+		},
+		gs:       GetGlobalShortcutModule(),
+		platform: js.Global.Get("process").Get("platform").String(),
+		entries:  make(map[string][]*shortcutEntry),
+	}
+	GetApp().On(EvtAppWillQuit, func(args ...*js.Object) {
+		m.UnregisterAll()
+	})
+	return m
+}
+
+func (m *ShortcutManager) key(a Accelerator) string {
+	return a.normalize(m.platform).String()
+}
+
+// conflict returns the registration clashing with a new one for window
+func (m *ShortcutManager) conflict(a Accelerator, window int64) *shortcutEntry {
+	for _, e := range m.entries[m.key(a)] {
+		if window == 0 || e.window == 0 || e.window == window {
+			return e
+		}
+	}
+	return nil
+}
+
+func (m *ShortcutManager) activate(e *shortcutEntry) error {
+	if e.active {
+		return nil
+	}
+	// focus of the next window may come before blur of the previous one
+	for _, x := range m.entries[m.key(e.acc)] {
+		m.deactivate(x)
+	}
+	if err := m.gs.RegisterEx(e.acc, e.fn); err != nil {
+		return err
+	}
+	e.active = true
+	return nil
+}
+
+func (m *ShortcutManager) deactivate(e *shortcutEntry) {
+	if e.active {
+		m.gs.UnregisterEx(e.acc)
+		e.active = false
+	}
+}
+
+// Register registers a as an application wide shortcut owned by owner.
+// A *ShortcutConflictError is returned when a is already registered through
+// the manager, and an error when another application holds it.
+func (m *ShortcutManager) Register(owner string, a Accelerator, fn func()) error {
+	if e := m.conflict(a, 0); e != nil {
+		return &ShortcutConflictError{Accelerator: a, Owner: e.owner}
+	}
+	e := &shortcutEntry{owner: owner, acc: a, fn: fn}
+	if err := m.activate(e); err != nil {
+		return err
+	}
+	k := m.key(a)
+	m.entries[k] = append(m.entries[k], e)
+	return nil
+}
+
+// RegisterForWindow registers a as a shortcut owned by owner which is only
+// active while bw is focused. The same accelerator can be registered for
+// different windows. The registration is removed when bw is closed.
+func (m *ShortcutManager) RegisterForWindow(owner string, bw *BrowserWindow, a Accelerator, fn func()) error {
+	if e := m.conflict(a, bw.Id); e != nil {
+		return &ShortcutConflictError{Accelerator: a, Owner: e.owner}
+	}
+	e := &shortcutEntry{owner: owner, acc: a, fn: fn, window: bw.Id}
+	if bw.IsFocused() {
+		if err := m.activate(e); err != nil {
+			return err
+		}
+	}
+	k := m.key(a)
+	m.entries[k] = append(m.entries[k], e)
+	onFocus := func(args ...*js.Object) {
+		if m.registered(e) {
+			if err := m.activate(e); err != nil && m.OnError != nil {
+				m.OnError(err)
+			}
+		}
+	}
+	onBlur := func(args ...*js.Object) {
+		m.deactivate(e)
+	}
+	onClosed := func(args ...*js.Object) {
+		m.remove(e)
+	}
+	bw.On(EvtBrowserWindowFocus, onFocus)
+	bw.On(EvtBrowserWindowBlur, onBlur)
+	bw.On(EvtBrowserWindowClosed, onClosed)
+	e.detach = func() {
+		bw.Call("removeListener", EvtBrowserWindowFocus, onFocus)
+		bw.Call("removeListener", EvtBrowserWindowBlur, onBlur)
+		bw.Call("removeListener", EvtBrowserWindowClosed, onClosed)
+	}
+	return nil
+}
+
+func (m *ShortcutManager) registered(e *shortcutEntry) bool {
+	for _, x := range m.entries[m.key(e.acc)] {
+		if x == e {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *ShortcutManager) remove(e *shortcutEntry) {
+	m.release(e)
+	k := m.key(e.acc)
+	list := m.entries[k]
+	for i, x := range list {
+		if x == e {
+			list = append(list[:i], list[i+1:]...)
+			break
+		}
+	}
+	if len(list) == 0 {
+		delete(m.entries, k)
+	} else {
+		m.entries[k] = list
+	}
+}
+
+// release unregisters e and removes the listeners of its window
+func (m *ShortcutManager) release(e *shortcutEntry) {
+	m.deactivate(e)
+	if e.detach != nil {
+		e.detach()
+		e.detach = nil
+	}
+}
+
+// Owners returns the owners of the registrations of a
+func (m *ShortcutManager) Owners(a Accelerator) []string {
+	owners := []string{}
+	for _, e := range m.entries[m.key(a)] {
+		owners = append(owners, e.owner)
+	}
+	return owners
+}
+
+// all returns a copy of the registrations, safe to range while removing
+func (m *ShortcutManager) all() []*shortcutEntry {
+	ret := []*shortcutEntry{}
+	for _, list := range m.entries {
+		ret = append(ret, list...)
+	}
+	return ret
+}
+
+// Unregister removes every registration of a
+func (m *ShortcutManager) Unregister(a Accelerator) {
+	k := m.key(a)
+	for _, e := range m.all() {
+		if m.key(e.acc) == k {
+			m.remove(e)
+		}
+	}
+}
+
+// UnregisterOwner removes every registration made by owner
+func (m *ShortcutManager) UnregisterOwner(owner string) {
+	for _, e := range m.all() {
+		if e.owner == owner {
+			m.remove(e)
+		}
+	}
+}
+
+// UnregisterAll removes every registration made through the manager
+func (m *ShortcutManager) UnregisterAll() {
+	for _, e := range m.all() {
+		m.release(e)
+	}
+	m.entries = make(map[string][]*shortcutEntry)
+}
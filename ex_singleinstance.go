@@ -0,0 +1,110 @@
+package electron
+
+import (
+	"flag"
+	"io/ioutil"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// InstanceArgs are the decoded command line arguments of an instance
+type InstanceArgs struct {
+	Argv             []string // raw argv, including the executable
+	WorkingDirectory string
+	Args             []string    // arguments remaining after the flags
+	Value            interface{} // as returned by SingleInstance.Flags
+	Err              error       // flag parsing error, Value is partially filled
+}
+
+// SingleInstance makes the application a single instance application: the
+// second instance forwards its command line to the primary one and quits.
+//
+//	si := &electron.SingleInstance{
+//		Flags: func(fs *flag.FlagSet) interface{} {
+//			o := &Options{}
+//			fs.StringVar(&o.Open, "open", "", "file to open")
+//			return o
+//		},
+//		Window: func() *electron.BrowserWindow { return mainWindow },
+//		OnSecondInstance: func(a *electron.InstanceArgs) {
+//			open(a.Value.(*Options).Open)
+//		},
+//	}
+//	if !si.Lock() {
+//		return
+//	}
+type SingleInstance struct {
+	// Flags defines the flags on fs and returns the value they are decoded
+	// into, usually a pointer to a struct. Optional.
+	Flags func(fs *flag.FlagSet) interface{}
+	// Window returns the window restored and focused when a second instance
+	// starts. Optional.
+	Window func() *BrowserWindow
+	// OnSecondInstance is called in the primary instance with the arguments
+	// of the second one, before the window is focused. Optional.
+	OnSecondInstance func(args *InstanceArgs)
+}
+
+// Lock takes the single instance lock. It returns true in the primary
+// instance. In a second instance the arguments have been forwarded, the app
+// is quitting and the caller should return without creating any window.
+func (s *SingleInstance) Lock() bool {
+	app := GetApp()
+	second := app.Call("makeSingleInstance", func(argv *js.Object, wd string) {
+		s.secondInstance(jsStrings(argv), wd)
+	}).Bool()
+	if second {
+		app.Quit()
+		return false
+	}
+	return true
+}
+
+// Release releases the lock, allowing other instances to run side by side
+func (s *SingleInstance) Release() {
+	GetApp().ReleaseSingleInstance()
+}
+
+func (s *SingleInstance) secondInstance(argv []string, wd string) {
+	if s.OnSecondInstance != nil {
+		s.OnSecondInstance(s.Parse(argv, wd))
+	}
+	if s.Window == nil {
+		return
+	}
+	if bw := s.Window(); bw != nil && !bw.IsDestroyed() {
+		focusWindow(bw)
+	}
+}
+
+// Current returns the decoded arguments of the running process
+func (s *SingleInstance) Current() *InstanceArgs {
+	process := js.Global.Get("process")
+	return s.Parse(jsStrings(process.Get("argv")), process.Call("cwd").String())
+}
+
+// Parse decodes argv with Flags. The executable is skipped, and so is the
+// app path when running through the electron binary, e.g. `electron .`.
+func (s *SingleInstance) Parse(argv []string, wd string) *InstanceArgs {
+	a := &InstanceArgs{
+		Argv:             argv,
+		WorkingDirectory: wd,
+	}
+	start := 1
+	if js.Global.Get("process").Get("defaultApp").Bool() {
+		start = 2
+	}
+	if start > len(argv) {
+		start = len(argv)
+	}
+	if s.Flags == nil {
+		a.Args = argv[start:]
+		return a
+	}
+	fs := flag.NewFlagSet(GetApp().GetName(), flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	a.Value = s.Flags(fs)
+	a.Err = fs.Parse(argv[start:])
+	a.Args = fs.Args()
+	return a
+}
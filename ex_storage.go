@@ -0,0 +1,58 @@
+package electron
+
+import (
+	"encoding/json"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// jsTry runs fn and returns the javascript exception it throws as an error
+func jsTry(fn func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			e, ok := r.(*js.Error)
+			if !ok {
+				panic(r)
+			}
+			err = e
+		}
+	}()
+	fn()
+	return nil
+}
+
+// isNotExist reports whether err is the ENOENT error of a node fs call
+func isNotExist(err error) bool {
+	e, ok := err.(*js.Error)
+	return ok && jsString(e.Get("code")) == "ENOENT"
+}
+
+// userDataPath returns the path of name inside `app.getPath("userData")`
+func userDataPath(name string) string {
+	dir := GetApp().GetPath("userData")
+	return require.Invoke("path").Call("join", dir, name).String()
+}
+
+// readJSONFile decodes the JSON file at path into v with node's fs module,
+// go's os package is not usable in electron
+func readJSONFile(path string, v interface{}) error {
+	var data string
+	err := jsTry(func() {
+		data = require.Invoke("fs").Call("readFileSync", path, "utf8").String()
+	})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(data), v)
+}
+
+// writeJSONFile encodes v as JSON into the file at path
+func writeJSONFile(path string, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return jsTry(func() {
+		require.Invoke("fs").Call("writeFileSync", path, string(b), "utf8")
+	})
+}
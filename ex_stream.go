@@ -0,0 +1,394 @@
+package electron
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// IPC channels used by streams, one for chunks and one for the acks
+const (
+	streamDataChannel = "electron-ex:stream-data"
+	streamAckChannel  = "electron-ex:stream-ack"
+)
+
+// StreamOptions tunes the chunking and flow control of SendStreamEx
+type StreamOptions struct {
+	ChunkSize int // bytes per chunk, default to 64KiB
+	Window    int // maximum number of chunks sent but not acked, default to 8, at most 64
+}
+
+func (o *StreamOptions) withDefaults() StreamOptions {
+	ret := StreamOptions{ChunkSize: 64 << 10, Window: 8}
+	if o != nil && o.ChunkSize > 0 {
+		ret.ChunkSize = o.ChunkSize
+	}
+	if o != nil && o.Window > 0 {
+		ret.Window = o.Window
+	}
+	if ret.Window > maxStreamWindow {
+		ret.Window = maxStreamWindow
+	}
+	return ret
+}
+
+// maxStreamWindow bounds the window a sender can ask the receiver to buffer
+const maxStreamWindow = 64
+
+// ErrStreamCancelled is returned on both sides of a stream cancelled by the
+// other side.
+var ErrStreamCancelled = errors.New("electron: stream cancelled")
+
+// OutStream is the sending side of a stream created by SendStreamEx
+type OutStream struct {
+	id     string
+	name   string
+	opt    StreamOptions
+	send   func(msg js.M)
+	acks   chan int
+	cancel chan struct{}
+	once   sync.Once
+	err    error // set before cancel is closed
+	done   chan struct{}
+	result error // set before done is closed
+	detach func()
+}
+
+// InStream is the receiving side of a stream, it is handed to the handler
+// registered with HandleStreamEx and implements io.ReadCloser.
+type InStream struct {
+	Name string
+	// Sender is the renderer sending the stream, nil in renderer processes
+	// where streams always come from the main process.
+	Sender *WebContents
+	key    string
+	ack    func(msg js.M)
+	chunks chan streamChunk
+	closed chan struct{}
+	once   sync.Once
+	buf    []byte
+	err    error
+}
+
+type streamChunk struct {
+	seq    int
+	data   []byte
+	eof    bool
+	cancel bool
+	err    string
+}
+
+// streamRouter dispatches the stream channels of the current process
+type streamRouter struct {
+	lastID   int
+	out      map[string]*OutStream
+	in       map[string]*InStream
+	handlers map[string]func(s *InStream)
+}
+
+var streams *streamRouter
+
+func getStreamRouter() *streamRouter {
+	if streams != nil {
+		return streams
+	}
+	streams = &streamRouter{
+		out:      make(map[string]*OutStream),
+		in:       make(map[string]*InStream),
+		handlers: make(map[string]func(s *InStream)),
+	}
+	if rendererProcess {
+		ipc := Get("ipcRenderer")
+		ipc.Call("on", streamDataChannel, func(event, msg *js.Object) {
+			streams.onData(nil, msg, func(m js.M) {
+				ipc.Call("send", streamAckChannel, m)
+			})
+		})
+		ipc.Call("on", streamAckChannel, func(event, msg *js.Object) {
+			streams.onAck(msg)
+		})
+		return streams
+	}
+	ipc := Get("ipcMain")
+	ipc.Call("on", streamDataChannel, func(event, msg *js.Object) {
+		sender := WrapWebContents(event.Get("sender"))
+		streams.onData(sender, msg, func(m js.M) {
+			if !sender.IsDestroyed() {
+				sender.Call("send", streamAckChannel, m)
+			}
+		})
+	})
+	ipc.Call("on", streamAckChannel, func(event, msg *js.Object) {
+		streams.onAck(msg)
+	})
+	return streams
+}
+
+func (r *streamRouter) onData(sender *WebContents, msg *js.Object, ack func(m js.M)) {
+	id := msg.Get("id").String()
+	key := "0/" + id
+	if sender != nil {
+		key = strconv.FormatInt(sender.Id, 10) + "/" + id
+	}
+	s, ok := r.in[key]
+	if !ok {
+		// only the first chunk opens a stream, the chunks in flight after a
+		// Close and the cancel of a finished stream are dropped. An empty or
+		// failing stream starts with its eof or error chunk.
+		if msg.Get("seq").Int() != 0 || msg.Get("cancel").Bool() {
+			return
+		}
+		name := msg.Get("name").String()
+		h := r.handlers[name]
+		if h == nil {
+			ack(js.M{"id": id, "cancel": true})
+			return
+		}
+		// the window comes from the other process
+		window := msg.Get("window").Int()
+		if window < 1 {
+			window = 1
+		} else if window > maxStreamWindow {
+			window = maxStreamWindow
+		}
+		s = &InStream{
+			Name:   name,
+			Sender: sender,
+			key:    key,
+			ack: func(m js.M) {
+				m["id"] = id
+				ack(m)
+			},
+			chunks: make(chan streamChunk, window+1),
+			closed: make(chan struct{}),
+		}
+		r.in[key] = s
+		go h(s)
+	}
+	c := streamChunk{seq: msg.Get("seq").Int()}
+	switch {
+	case msg.Get("cancel").Bool():
+		c.cancel = true
+	case msg.Get("eof").Bool():
+		c.eof = true
+	case !isNullish(msg.Get("error")):
+		c.err = msg.Get("error").String()
+	default:
+		data, err := base64.StdEncoding.DecodeString(msg.Get("data").String())
+		if err != nil {
+			c.err = err.Error()
+		}
+		c.data = data
+	}
+	if c.cancel || c.eof || c.err != "" {
+		delete(r.in, key)
+	}
+	select {
+	case s.chunks <- c:
+	default:
+		// the sender ignored the flow control window
+		s.Close()
+	}
+}
+
+func (r *streamRouter) onAck(msg *js.Object) {
+	s, ok := r.out[msg.Get("id").String()]
+	if !ok {
+		return
+	}
+	if msg.Get("cancel").Bool() {
+		s.abort(ErrStreamCancelled, false)
+		return
+	}
+	select {
+	case s.acks <- msg.Get("seq").Int():
+	default:
+	}
+}
+
+// SendStreamEx splits the content of r into chunks and sends them to the
+// receiving side registered under name with HandleStreamEx. At most
+// opt.Window chunks are in flight at any time, the next ones are read from r
+// only once the receiver has consumed and acked the previous ones.
+//
+// In the main process wc is the receiving renderer, in renderer processes wc
+// must be nil and the stream goes to the main process. opt can be nil.
+//
+// Sending happens in its own goroutine, use Wait to get the result.
+func SendStreamEx(wc *WebContents, name string, r io.Reader, opt *StreamOptions) *OutStream {
+	router := getStreamRouter()
+	router.lastID++
+	s := &OutStream{
+		id:     processType() + "-" + strconv.Itoa(router.lastID),
+		name:   name,
+		opt:    opt.withDefaults(),
+		cancel: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	s.acks = make(chan int, s.opt.Window+1)
+	if wc == nil {
+		ipc := Get("ipcRenderer")
+		s.send = func(msg js.M) {
+			ipc.Call("send", streamDataChannel, msg)
+		}
+	} else {
+		s.send = func(msg js.M) {
+			if wc.IsDestroyed() {
+				s.abort(errors.New("electron: stream receiver destroyed"), false)
+				return
+			}
+			wc.Call("send", streamDataChannel, msg)
+		}
+		onDestroyed := func(args ...*js.Object) {
+			s.abort(errors.New("electron: stream receiver destroyed"), false)
+		}
+		wc.On(EvtWebContentsDestroyed, onDestroyed)
+		s.detach = func() {
+			wc.Call("removeListener", EvtWebContentsDestroyed, onDestroyed)
+		}
+	}
+	router.out[s.id] = s
+	go s.run(r)
+	return s
+}
+
+func (s *OutStream) message(seq int) js.M {
+	return js.M{"id": s.id, "name": s.name, "window": s.opt.Window, "seq": seq}
+}
+
+func (s *OutStream) run(r io.Reader) {
+	defer func() {
+		delete(streams.out, s.id)
+		if s.detach != nil {
+			s.detach()
+		}
+		close(s.done)
+	}()
+	buf := make([]byte, s.opt.ChunkSize)
+	seq, acked := 0, -1
+	// wait blocks until fewer than max chunks are in flight
+	wait := func(max int) bool {
+		for seq-acked-1 >= max {
+			select {
+			case a := <-s.acks:
+				if a > acked {
+					acked = a
+				}
+			case <-s.cancel:
+				s.result = s.err
+				return false
+			}
+		}
+		return true
+	}
+	for {
+		if !wait(s.opt.Window) {
+			return
+		}
+		n, err := io.ReadFull(r, buf)
+		if err == io.ErrUnexpectedEOF {
+			err = io.EOF
+		}
+		if n > 0 {
+			msg := s.message(seq)
+			msg["data"] = base64.StdEncoding.EncodeToString(buf[:n])
+			s.send(msg)
+			seq++
+		}
+		if err == io.EOF {
+			msg := s.message(seq)
+			msg["eof"] = true
+			s.send(msg)
+			seq++
+			// done once the receiver has read everything
+			if wait(1) {
+				s.result = nil
+			}
+			return
+		}
+		if err != nil {
+			msg := s.message(seq)
+			msg["error"] = err.Error()
+			s.send(msg)
+			s.result = err
+			return
+		}
+	}
+}
+
+func (s *OutStream) abort(err error, notify bool) {
+	s.once.Do(func() {
+		s.err = err
+		close(s.cancel)
+		if notify {
+			msg := s.message(0)
+			msg["cancel"] = true
+			s.send(msg)
+		}
+	})
+}
+
+// Cancel stops sending, the receiver gets ErrStreamCancelled from Read
+func (s *OutStream) Cancel() {
+	s.abort(ErrStreamCancelled, true)
+}
+
+// Wait blocks until the whole stream has been consumed by the receiver, or
+// the stream failed or was cancelled.
+func (s *OutStream) Wait() error {
+	<-s.done
+	return s.result
+}
+
+// HandleStreamEx registers h to be called, in its own goroutine, for every
+// stream sent with SendStreamEx under name. Streams without a handler are
+// cancelled.
+func HandleStreamEx(name string, h func(s *InStream)) {
+	getStreamRouter().handlers[name] = h
+}
+
+// Read implements io.Reader, it blocks until the next chunk arrives
+func (s *InStream) Read(p []byte) (int, error) {
+	for len(s.buf) == 0 {
+		if s.err != nil {
+			return 0, s.err
+		}
+		select {
+		case c := <-s.chunks:
+			switch {
+			case c.cancel:
+				s.err = ErrStreamCancelled
+			case c.err != "":
+				s.err = fmt.Errorf("electron: stream %q: %s", s.Name, c.err)
+			case c.eof:
+				s.err = io.EOF
+				s.ack(js.M{"seq": c.seq})
+			default:
+				s.buf = c.data
+				s.ack(js.M{"seq": c.seq})
+			}
+		case <-s.closed:
+			s.err = io.ErrClosedPipe
+		}
+	}
+	n := copy(p, s.buf)
+	s.buf = s.buf[n:]
+	return n, nil
+}
+
+// Close cancels the stream, the sender's Wait returns ErrStreamCancelled
+func (s *InStream) Close() error {
+	s.once.Do(func() {
+		close(s.closed)
+		if streams.in[s.key] == s {
+			delete(streams.in, s.key)
+			s.ack(js.M{"cancel": true})
+		}
+	})
+	return nil
+}
@@ -0,0 +1,413 @@
+package electron
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// resource types of webRequest details
+const (
+	ResourceMainFrame  = "mainFrame"
+	ResourceSubFrame   = "subFrame"
+	ResourceStylesheet = "stylesheet"
+	ResourceScript     = "script"
+	ResourceImage      = "image"
+	ResourceObject     = "object"
+	ResourceXHR        = "xhr"
+	ResourceOther      = "other"
+)
+
+// RequestDetails are the details of a request given to the webRequest rules
+type RequestDetails struct {
+	ID           int64
+	URL          string
+	Method       string
+	ResourceType string
+	Referrer     string
+	StatusCode   int    // response events only
+	StatusLine   string // response events only, rules of OnHeadersReceived can change it
+	Object       *js.Object
+}
+
+func newRequestDetails(o *js.Object) *RequestDetails {
+	d := &RequestDetails{
+		ID:           o.Get("id").Int64(),
+		URL:          jsString(o.Get("url")),
+		Method:       jsString(o.Get("method")),
+		ResourceType: jsString(o.Get("resourceType")),
+		Referrer:     jsString(o.Get("referrer")),
+		StatusLine:   jsString(o.Get("statusLine")),
+		Object:       o,
+	}
+	if code := o.Get("statusCode"); !isNullish(code) {
+		d.StatusCode = code.Int()
+	}
+	return d
+}
+
+// RequestVerdict is the decision of an OnBeforeRequest rule
+type RequestVerdict struct {
+	Cancel      bool
+	RedirectURL string
+}
+
+// WebRequestRule is a rule of a WebRequestPipeline, every hook is optional.
+// Hooks are called from the webRequest listeners and must not block.
+type WebRequestRule struct {
+	// URLs are the match patterns of the requests the rule applies to, e.g.
+	// "*://*.example.com/*" or "<all_urls>", all requests when empty. They
+	// follow the chrome match patterns: `<scheme>://<host><path>` where the
+	// scheme `*` is http or https, the host `*.example.com` is example.com or
+	// one of its subdomains and a `*` of the path matches anything.
+	URLs []string
+	// ResourceTypes restricts the rule to the resource types, e.g.
+	// ResourceScript, all types when empty
+	ResourceTypes []string
+
+	// OnBeforeRequest can cancel or redirect the request, nil lets the next
+	// rules decide
+	OnBeforeRequest func(d *RequestDetails) *RequestVerdict
+	// OnBeforeSendHeaders can change the request headers, returning true
+	// cancels the request
+	OnBeforeSendHeaders func(d *RequestDetails, header http.Header) (cancel bool)
+	// OnHeadersReceived can change the response headers and d.StatusLine,
+	// returning true cancels the request
+	OnHeadersReceived func(d *RequestDetails, header http.Header) (cancel bool)
+
+	patterns []*urlPattern
+}
+
+// urlPattern is a parsed match pattern, the zero value matches every url
+type urlPattern struct {
+	scheme     string // empty for any, "*" for http and https
+	host       string // empty for any
+	subdomains bool
+	path       *regexp.Regexp
+}
+
+var urlPatternScheme = regexp.MustCompile(`^(\*|[a-z][a-z0-9+.-]*)$`)
+
+// parseURLPattern parses a chrome match pattern
+func parseURLPattern(p string) (*urlPattern, error) {
+	if p == "<all_urls>" {
+		return &urlPattern{}, nil
+	}
+	invalid := func(reason string) error {
+		return fmt.Errorf("electron: invalid url pattern %q: %s", p, reason)
+	}
+	i := strings.Index(p, "://")
+	if i < 0 {
+		return nil, invalid("missing ://")
+	}
+	up := &urlPattern{scheme: strings.ToLower(p[:i])}
+	if !urlPatternScheme.MatchString(up.scheme) {
+		return nil, invalid("bad scheme")
+	}
+	rest := p[i+3:]
+	j := strings.Index(rest, "/")
+	if j < 0 {
+		return nil, invalid("missing path")
+	}
+	host := strings.ToLower(rest[:j])
+	switch {
+	case host == "*":
+	case strings.HasPrefix(host, "*."):
+		up.host, up.subdomains = host[2:], true
+	default:
+		up.host = host
+	}
+	switch {
+	case strings.Contains(up.host, "*") || (up.subdomains && up.host == ""):
+		return nil, invalid("`*` must be the whole host or its first label")
+	case host == "" && up.scheme != "file":
+		return nil, invalid("missing host")
+	}
+	quoted := strings.Split(rest[j:], "*")
+	for i := range quoted {
+		quoted[i] = regexp.QuoteMeta(quoted[i])
+	}
+	up.path = regexp.MustCompile("^" + strings.Join(quoted, ".*") + "$")
+	return up, nil
+}
+
+func (up *urlPattern) match(u *url.URL) bool {
+	scheme := strings.ToLower(u.Scheme)
+	switch up.scheme {
+	case "":
+		return true
+	case "*":
+		if scheme != "http" && scheme != "https" {
+			return false
+		}
+	default:
+		if scheme != up.scheme {
+			return false
+		}
+	}
+	if up.host != "" {
+		host := strings.ToLower(u.Hostname())
+		if strings.Contains(up.host, ":") {
+			host = strings.ToLower(u.Host)
+		}
+		if host != up.host && !(up.subdomains && strings.HasSuffix(host, "."+up.host)) {
+			return false
+		}
+	}
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	return up.path.MatchString(path)
+}
+
+// compile parses the match patterns of r
+func (r *WebRequestRule) compile() error {
+	patterns := []*urlPattern{}
+	for _, p := range r.URLs {
+		up, err := parseURLPattern(p)
+		if err != nil {
+			return err
+		}
+		patterns = append(patterns, up)
+	}
+	r.patterns = patterns
+	return nil
+}
+
+func (r *WebRequestRule) match(d *RequestDetails) bool {
+	if len(r.ResourceTypes) > 0 {
+		ok := false
+		for _, t := range r.ResourceTypes {
+			if t == d.ResourceType {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	if len(r.patterns) == 0 {
+		return true
+	}
+	u, err := url.Parse(d.URL)
+	if err != nil {
+		return false
+	}
+	for _, up := range r.patterns {
+		if up.match(u) {
+			return true
+		}
+	}
+	return false
+}
+
+// WebRequestPipeline composes webRequest rules: electron only keeps one
+// listener per event and session, the pipeline installs it and runs its
+// rules in the order they were added.
+type WebRequestPipeline struct {
+	wr        *js.Object
+	rules     []*WebRequestRule
+	installed map[string]bool
+}
+
+// NewWebRequestPipelineEx creates the pipeline of session s, or of the
+// default session when s is nil. It replaces the webRequest listeners set
+// otherwise on the session.
+func NewWebRequestPipelineEx(s *Session) *WebRequestPipeline {
+	if s == nil {
+		s = GetSessionModule().DefaultSession
+	}
+	return &WebRequestPipeline{
+		wr:        s.Get("webRequest"),
+		installed: make(map[string]bool),
+	}
+}
+
+// Add appends rule to the pipeline, remove takes it out. It fails when a
+// pattern of rule.URLs is malformed.
+func (p *WebRequestPipeline) Add(rule *WebRequestRule) (remove func(), err error) {
+	if err := rule.compile(); err != nil {
+		return nil, err
+	}
+	p.rules = append(p.rules, rule)
+	if rule.OnBeforeRequest != nil {
+		p.install("onBeforeRequest", p.beforeRequest)
+	}
+	if rule.OnBeforeSendHeaders != nil {
+		p.install("onBeforeSendHeaders", p.beforeSendHeaders)
+	}
+	if rule.OnHeadersReceived != nil {
+		p.install("onHeadersReceived", p.headersReceived)
+	}
+	return func() {
+		for i, r := range p.rules {
+			if r == rule {
+				p.rules = append(p.rules[:i:i], p.rules[i+1:]...)
+				return
+			}
+		}
+	}, nil
+}
+
+func (p *WebRequestPipeline) install(event string, listener func(details, callback *js.Object)) {
+	if !p.installed[event] {
+		p.installed[event] = true
+		p.wr.Call(event, listener)
+	}
+}
+
+// matching returns a copy of the rules applying to d, rules can be removed
+// while running
+func (p *WebRequestPipeline) matching(d *RequestDetails) []*WebRequestRule {
+	ret := []*WebRequestRule{}
+	for _, r := range p.rules {
+		if r.match(d) {
+			ret = append(ret, r)
+		}
+	}
+	return ret
+}
+
+func (p *WebRequestPipeline) beforeRequest(details, callback *js.Object) {
+	d := newRequestDetails(details)
+	for _, r := range p.matching(d) {
+		if r.OnBeforeRequest == nil {
+			continue
+		}
+		if v := r.OnBeforeRequest(d); v != nil {
+			if v.Cancel {
+				callback.Invoke(js.M{"cancel": true})
+				return
+			}
+			if v.RedirectURL != "" {
+				callback.Invoke(js.M{"redirectURL": v.RedirectURL})
+				return
+			}
+		}
+	}
+	callback.Invoke(js.M{})
+}
+
+func (p *WebRequestPipeline) beforeSendHeaders(details, callback *js.Object) {
+	d := newRequestDetails(details)
+	header := headerFromJs(details.Get("requestHeaders"))
+	for _, r := range p.matching(d) {
+		if r.OnBeforeSendHeaders != nil && r.OnBeforeSendHeaders(d, header) {
+			callback.Invoke(js.M{"cancel": true})
+			return
+		}
+	}
+	h := js.M{}
+	for k, v := range header {
+		h[k] = strings.Join(v, ", ")
+	}
+	callback.Invoke(js.M{"requestHeaders": h})
+}
+
+func (p *WebRequestPipeline) headersReceived(details, callback *js.Object) {
+	d := newRequestDetails(details)
+	header := headerFromJs(details.Get("responseHeaders"))
+	for _, r := range p.matching(d) {
+		if r.OnHeadersReceived != nil && r.OnHeadersReceived(d, header) {
+			callback.Invoke(js.M{"cancel": true})
+			return
+		}
+	}
+	h := js.M{}
+	for k, v := range header {
+		h[k] = v
+	}
+	ret := js.M{"responseHeaders": h}
+	if d.StatusLine != "" {
+		ret["statusLine"] = d.StatusLine
+	}
+	callback.Invoke(ret)
+}
+
+// headerFromJs converts the request headers, strings, or the response
+// headers, arrays of strings, of webRequest details
+func headerFromJs(o *js.Object) http.Header {
+	h := http.Header{}
+	if isNullish(o) {
+		return h
+	}
+	keys := js.Global.Get("Object").Call("keys", o)
+	for i := 0; i < keys.Length(); i++ {
+		k := keys.Index(i).String()
+		v := o.Get(k)
+		if js.Global.Get("Array").Call("isArray", v).Bool() {
+			for _, s := range jsStrings(v) {
+				h.Add(k, s)
+			}
+		} else {
+			h.Add(k, v.String())
+		}
+	}
+	return h
+}
+
+// CancelRequestsRule cancels the requests matching urls
+func CancelRequestsRule(urls ...string) *WebRequestRule {
+	return &WebRequestRule{
+		URLs: urls,
+		OnBeforeRequest: func(d *RequestDetails) *RequestVerdict {
+			return &RequestVerdict{Cancel: true}
+		},
+	}
+}
+
+// RedirectRule redirects the requests matching urls to the url returned by
+// to, an empty url leaves the request alone
+func RedirectRule(to func(d *RequestDetails) string, urls ...string) *WebRequestRule {
+	return &WebRequestRule{
+		URLs: urls,
+		OnBeforeRequest: func(d *RequestDetails) *RequestVerdict {
+			if u := to(d); u != "" && u != d.URL {
+				return &RequestVerdict{RedirectURL: u}
+			}
+			return nil
+		},
+	}
+}
+
+// RequestHeaderRule sets the request headers of set and removes those of
+// strip on the requests matching urls
+func RequestHeaderRule(set http.Header, strip []string, urls ...string) *WebRequestRule {
+	return &WebRequestRule{
+		URLs: urls,
+		OnBeforeSendHeaders: func(d *RequestDetails, header http.Header) bool {
+			rewriteHeader(header, set, strip)
+			return false
+		},
+	}
+}
+
+// ResponseHeaderRule sets the response headers of set and removes those of
+// strip on the requests matching urls
+func ResponseHeaderRule(set http.Header, strip []string, urls ...string) *WebRequestRule {
+	return &WebRequestRule{
+		URLs: urls,
+		OnHeadersReceived: func(d *RequestDetails, header http.Header) bool {
+			rewriteHeader(header, set, strip)
+			return false
+		},
+	}
+}
+
+func rewriteHeader(header, set http.Header, strip []string) {
+	for _, k := range strip {
+		header.Del(k)
+	}
+	for k, v := range set {
+		header[http.CanonicalHeaderKey(k)] = v
+	}
+}
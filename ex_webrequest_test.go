@@ -0,0 +1,108 @@
+package electron
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestURLPatternMatch(t *testing.T) {
+	tests := []struct {
+		pattern, url string
+		want         bool
+	}{
+		{"<all_urls>", "https://example.com/", true},
+		{"<all_urls>", "app://ui/index.html", true},
+		{"*://*/*", "http://example.com/", true},
+		{"*://*/*", "https://example.com/a?b", true},
+		{"*://*/*", "ftp://example.com/", false},
+		{"*://*/*", "file:///tmp/a", false},
+		{"*://*.example.com/*", "https://example.com/", true},
+		{"*://*.example.com/*", "https://a.b.example.com/p", true},
+		{"*://*.example.com/*", "https://EXAMPLE.com/", true},
+		{"*://*.example.com/*", "https://evil.com/x.example.com/", false},
+		{"*://*.example.com/*", "https://notexample.com/", false},
+		{"*://*.example.com/*", "https://example.com.evil.com/", false},
+		{"https://example.com/*", "http://example.com/", false},
+		{"https://example.com/*", "https://example.com:8443/", true},
+		{"https://example.com/*", "https://sub.example.com/", false},
+		{"https://example.com/api/*", "https://example.com/api/v1?x=1", true},
+		{"https://example.com/api/*", "https://example.com/apix", false},
+		{"https://example.com/*.js", "https://example.com/a/b.js", true},
+		{"https://example.com/*.js", "https://example.com/a.json", false},
+		{"https://example.com/", "https://example.com", true},
+		{"https://example.com/", "https://example.com/a", false},
+		{"http://localhost:8080/*", "http://localhost:8080/a", true},
+		{"http://localhost:8080/*", "http://localhost:9090/a", false},
+		{"file:///*", "file:///tmp/a.html", true},
+		{"file:///tmp/*", "file:///etc/passwd", false},
+		{"app://ui/*", "app://ui/index.html", true},
+		{"app://ui/*", "app://other/index.html", false},
+	}
+	for _, tt := range tests {
+		up, err := parseURLPattern(tt.pattern)
+		if err != nil {
+			t.Errorf("parseURLPattern(%q): %v", tt.pattern, err)
+			continue
+		}
+		u, err := url.Parse(tt.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := up.match(u); got != tt.want {
+			t.Errorf("%q matching %q = %v, want %v", tt.pattern, tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestURLPatternInvalid(t *testing.T) {
+	for _, p := range []string{
+		"*",
+		"",
+		"example.com/*",
+		"https://example.com",
+		"https://*.",
+		"https://*./",
+		"https://a*b.com/",
+		"https://*example.com/",
+		"https://www.*.com/",
+		"https:///x",
+		"ht tp://x/",
+		"1http://x/",
+	} {
+		if _, err := parseURLPattern(p); err == nil {
+			t.Errorf("parseURLPattern(%q) succeeded, want an error", p)
+		}
+	}
+}
+
+func TestWebRequestRuleMatch(t *testing.T) {
+	r := &WebRequestRule{
+		URLs:          []string{"https://a.com/*", "*://*.b.com/*"},
+		ResourceTypes: []string{ResourceScript},
+	}
+	if err := r.compile(); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		url, resourceType string
+		want              bool
+	}{
+		{"https://a.com/x.js", ResourceScript, true},
+		{"http://cdn.b.com/x.js", ResourceScript, true},
+		{"https://a.com/x.js", ResourceImage, false},
+		{"https://c.com/a.com/", ResourceScript, false},
+		{"::not a url", ResourceScript, false},
+	}
+	for _, tt := range tests {
+		d := &RequestDetails{URL: tt.url, ResourceType: tt.resourceType}
+		if got := r.match(d); got != tt.want {
+			t.Errorf("match(%q, %q) = %v, want %v", tt.url, tt.resourceType, got, tt.want)
+		}
+	}
+	if err := (&WebRequestRule{URLs: []string{"https://a.com"}}).compile(); err == nil {
+		t.Error("compile succeeded with a malformed pattern")
+	}
+	if !(&WebRequestRule{}).match(&RequestDetails{URL: "https://any.com/"}) {
+		t.Error("a rule without patterns must match every request")
+	}
+}
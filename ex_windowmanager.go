@@ -0,0 +1,173 @@
+package electron
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// WindowFactory creates a window from the options prepared by the
+// WindowManager, parent and modal are already set for child windows
+type WindowFactory func(opt *BrowserWindowBrowserWindowOptions) *BrowserWindow
+
+// WindowManager keeps the named windows of the main process, e.g. "main",
+// "settings" or "about". Opening a name which is already open focuses the
+// existing window, and entries are removed when their window is closed.
+type WindowManager struct {
+	windows map[string]*managedWindow
+}
+
+type managedWindow struct {
+	name     string
+	id       int64
+	wcID     int64
+	bw       *BrowserWindow
+	parent   string
+	children map[string]bool
+}
+
+// NewWindowManagerEx creates an empty WindowManager, it must be used in the
+// main process
+func NewWindowManagerEx() *WindowManager {
+	return &WindowManager{
+		windows: make(map[string]*managedWindow),
+	}
+}
+
+// Open returns the window called name, focusing it when it is already open
+// and creating it with factory otherwise
+func (m *WindowManager) Open(name string, factory WindowFactory) *BrowserWindow {
+	bw, _ := m.open(name, "", false, factory)
+	return bw
+}
+
+// OpenChild is Open for a window whose parent is the open window called
+// parent, modal makes the child a modal window of its parent
+func (m *WindowManager) OpenChild(parent, name string, modal bool, factory WindowFactory) (*BrowserWindow, error) {
+	return m.open(name, parent, modal, factory)
+}
+
+func (m *WindowManager) open(name, parent string, modal bool, factory WindowFactory) (*BrowserWindow, error) {
+	if w, ok := m.windows[name]; ok {
+		focusWindow(w.bw)
+		return w.bw, nil
+	}
+	opt := NewBrowserWindowOption()
+	var p *managedWindow
+	if parent != "" {
+		p = m.windows[parent]
+		if p == nil {
+			return nil, fmt.Errorf("electron: no open window called %q", parent)
+		}
+		opt.Set("parent", p.bw.Object)
+		opt.Modal = modal
+	}
+	bw := factory(opt)
+	w := &managedWindow{
+		name:     name,
+		id:       bw.Id,
+		wcID:     bw.WebContents.Id,
+		bw:       bw,
+		parent:   parent,
+		children: make(map[string]bool),
+	}
+	m.windows[name] = w
+	if p != nil {
+		p.children[name] = true
+	}
+	bw.On(EvtBrowserWindowClosed, func(args ...*js.Object) {
+		m.forget(w)
+	})
+	return bw, nil
+}
+
+func focusWindow(bw *BrowserWindow) {
+	if bw.IsMinimized() {
+		bw.Restore()
+	}
+	bw.Show()
+	bw.Focus()
+}
+
+func (m *WindowManager) forget(w *managedWindow) {
+	if m.windows[w.name] != w {
+		return
+	}
+	delete(m.windows, w.name)
+	if p, ok := m.windows[w.parent]; ok {
+		delete(p.children, w.name)
+	}
+	// electron closes child windows with their parent, forget them as well
+	for child := range w.children {
+		if c, ok := m.windows[child]; ok {
+			m.forget(c)
+		}
+	}
+}
+
+// Get returns the open window called name, or nil
+func (m *WindowManager) Get(name string) *BrowserWindow {
+	if w, ok := m.windows[name]; ok {
+		return w.bw
+	}
+	return nil
+}
+
+// FromID returns the name and window with the BrowserWindow id
+func (m *WindowManager) FromID(id int64) (string, *BrowserWindow) {
+	for _, w := range m.windows {
+		if w.id == id {
+			return w.name, w.bw
+		}
+	}
+	return "", nil
+}
+
+// FromWebContents returns the name and window owning wc, e.g. the sender of
+// an IPC message
+func (m *WindowManager) FromWebContents(wc *WebContents) (string, *BrowserWindow) {
+	for _, w := range m.windows {
+		if w.wcID == wc.Id {
+			return w.name, w.bw
+		}
+	}
+	return "", nil
+}
+
+// Parent returns the name of the parent of the window called name
+func (m *WindowManager) Parent(name string) string {
+	if w, ok := m.windows[name]; ok {
+		return w.parent
+	}
+	return ""
+}
+
+// Children returns the names of the open child windows of name, sorted
+func (m *WindowManager) Children(name string) []string {
+	ret := []string{}
+	if w, ok := m.windows[name]; ok {
+		for child := range w.children {
+			ret = append(ret, child)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Names returns the names of the open windows, sorted
+func (m *WindowManager) Names() []string {
+	ret := make([]string, 0, len(m.windows))
+	for name := range m.windows {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Close closes the window called name, its children are closed by electron
+func (m *WindowManager) Close(name string) {
+	if w, ok := m.windows[name]; ok {
+		w.bw.Close()
+	}
+}
@@ -0,0 +1,600 @@
+package electron
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// WindowOptions builds BrowserWindow options with typed setters for every
+// BrowserWindow and webPreferences option of the bundled api files.
+// Only the options explicitly set are passed to electron, the others keep
+// electron's defaults.
+//
+// Setters can be chained:
+//
+//	bw, err := electron.NewWindowOptionsEx().Size(1024, 768).Frame(false).NodeIntegration(false).New()
+type WindowOptions struct {
+	opts    map[string]interface{}
+	web     map[string]interface{}
+	parent  *BrowserWindow
+	icon    *NativeImage
+	session *Session
+}
+
+var defaultWindowOptions = &WindowOptions{
+	opts: map[string]interface{}{"width": 800, "height": 600},
+	web:  map[string]interface{}{},
+}
+
+// SetDefaultWindowOptionsEx makes NewWindowOptionsEx start from a copy of o
+func SetDefaultWindowOptionsEx(o *WindowOptions) {
+	defaultWindowOptions = o.Clone()
+}
+
+// NewWindowOptionsEx returns a copy of the default options, a 800x600 window
+// unless changed with SetDefaultWindowOptionsEx
+func NewWindowOptionsEx() *WindowOptions {
+	return defaultWindowOptions.Clone()
+}
+
+// Clone returns a copy of o, to use o as a template for several windows
+func (o *WindowOptions) Clone() *WindowOptions {
+	c := &WindowOptions{
+		opts:    make(map[string]interface{}, len(o.opts)),
+		web:     make(map[string]interface{}, len(o.web)),
+		parent:  o.parent,
+		icon:    o.icon,
+		session: o.session,
+	}
+	for k, v := range o.opts {
+		c.opts[k] = v
+	}
+	for k, v := range o.web {
+		c.web[k] = v
+	}
+	return c
+}
+
+func (o *WindowOptions) set(name string, v interface{}) *WindowOptions {
+	o.opts[name] = v
+	return o
+}
+
+func (o *WindowOptions) setWeb(name string, v interface{}) *WindowOptions {
+	o.web[name] = v
+	return o
+}
+
+// Unset removes the option name, a webPreferences option when web is true,
+// so that electron's default is used
+func (o *WindowOptions) Unset(name string, web bool) *WindowOptions {
+	if web {
+		delete(o.web, name)
+	} else {
+		delete(o.opts, name)
+	}
+	return o
+}
+
+// Size sets width and height
+func (o *WindowOptions) Size(width, height int) *WindowOptions {
+	return o.Width(width).Height(height)
+}
+
+// Position sets x and y - Window's left and top offset from screen, electron
+// requires both
+func (o *WindowOptions) Position(x, y int) *WindowOptions {
+	return o.set("x", x).set("y", y)
+}
+
+// Parent sets parent - Specify parent window.
+func (o *WindowOptions) Parent(bw *BrowserWindow) *WindowOptions {
+	o.parent = bw
+	return o
+}
+
+// Icon sets icon - The window icon.
+func (o *WindowOptions) Icon(icon *NativeImage) *WindowOptions {
+	o.icon = icon
+	return o
+}
+
+// IconPath sets icon to the path of an image file
+func (o *WindowOptions) IconPath(path string) *WindowOptions {
+	o.icon = nil
+	return o.set("icon", path)
+}
+
+// BackgroundColor sets backgroundColor - Window's background color as
+// Hexadecimal value, like #66CD00 or #FFF or #80FFFFFF (alpha is supported).
+func (o *WindowOptions) BackgroundColor(v string) *WindowOptions {
+	return o.set("backgroundColor", v)
+}
+
+// TitleBarStyle sets titleBarStyle - The style of window title bar, see
+// BrowserWindowOptionsTitleBarStyle values.
+func (o *WindowOptions) TitleBarStyle(v BrowserWindowOptionsTitleBarStyle) *WindowOptions {
+	return o.set("titleBarStyle", string(v))
+}
+
+// Vibrancy sets vibrancy - Add a type of vibrancy effect to the window, only
+// on macOS, see BrowserWindowOptionsVibrancy values.
+func (o *WindowOptions) Vibrancy(v BrowserWindowOptionsVibrancy) *WindowOptions {
+	return o.set("vibrancy", string(v))
+}
+
+// Session sets the webPreferences session - Sets the session used by the
+// page. Use Partition to pass a partition string instead.
+func (o *WindowOptions) Session(s *Session) *WindowOptions {
+	o.session = s
+	return o
+}
+
+// ZoomFactor sets the webPreferences zoomFactor - The default zoom factor of
+// the page, 3.0 represents 300%.
+func (o *WindowOptions) ZoomFactor(v float64) *WindowOptions {
+	return o.setWeb("zoomFactor", v)
+}
+
+// DefaultFontFamily sets the webPreferences defaultFontFamily, empty values
+// are left to electron's defaults
+func (o *WindowOptions) DefaultFontFamily(standard, serif, sansSerif, monospace string) *WindowOptions {
+	m := js.M{}
+	for k, v := range map[string]string{"standard": standard, "serif": serif, "sansSerif": sansSerif, "monospace": monospace} {
+		if v != "" {
+			m[k] = v
+		}
+	}
+	return o.setWeb("defaultFontFamily", m)
+}
+
+// Width sets width - Window's width in pixels.
+func (o *WindowOptions) Width(v int) *WindowOptions {
+	return o.set("width", v)
+}
+
+// Height sets height - Window's height in pixels.
+func (o *WindowOptions) Height(v int) *WindowOptions {
+	return o.set("height", v)
+}
+
+// UseContentSize sets useContentSize
+func (o *WindowOptions) UseContentSize(v bool) *WindowOptions {
+	return o.set("useContentSize", v)
+}
+
+// Center sets center - Show window in the center of the screen.
+func (o *WindowOptions) Center(v bool) *WindowOptions {
+	return o.set("center", v)
+}
+
+// MinWidth sets minWidth - Window's minimum width.
+func (o *WindowOptions) MinWidth(v int) *WindowOptions {
+	return o.set("minWidth", v)
+}
+
+// MinHeight sets minHeight - Window's minimum height.
+func (o *WindowOptions) MinHeight(v int) *WindowOptions {
+	return o.set("minHeight", v)
+}
+
+// MaxWidth sets maxWidth - Window's maximum width. Default is no limit.
+func (o *WindowOptions) MaxWidth(v int) *WindowOptions {
+	return o.set("maxWidth", v)
+}
+
+// MaxHeight sets maxHeight - Window's maximum height. Default is no limit.
+func (o *WindowOptions) MaxHeight(v int) *WindowOptions {
+	return o.set("maxHeight", v)
+}
+
+// Resizable sets resizable - Whether window is resizable.
+func (o *WindowOptions) Resizable(v bool) *WindowOptions {
+	return o.set("resizable", v)
+}
+
+// Movable sets movable - Whether window is movable. This is not implemented on Linux.
+func (o *WindowOptions) Movable(v bool) *WindowOptions {
+	return o.set("movable", v)
+}
+
+// Minimizable sets minimizable - Whether window is minimizable. This is not implemented on Linux.
+func (o *WindowOptions) Minimizable(v bool) *WindowOptions {
+	return o.set("minimizable", v)
+}
+
+// Maximizable sets maximizable - Whether window is maximizable. This is not implemented on Linux.
+func (o *WindowOptions) Maximizable(v bool) *WindowOptions {
+	return o.set("maximizable", v)
+}
+
+// Closable sets closable - Whether window is closable. This is not implemented on Linux.
+func (o *WindowOptions) Closable(v bool) *WindowOptions {
+	return o.set("closable", v)
+}
+
+// Focusable sets focusable - Whether the window can be focused. On Linux setting makes the window
+// stop interacting with wm, so the window will always stay on top in all workspaces.
+func (o *WindowOptions) Focusable(v bool) *WindowOptions {
+	return o.set("focusable", v)
+}
+
+// AlwaysOnTop sets alwaysOnTop - Whether the window should always stay on top of other windows.
+func (o *WindowOptions) AlwaysOnTop(v bool) *WindowOptions {
+	return o.set("alwaysOnTop", v)
+}
+
+// Fullscreen sets fullscreen - Whether the window should show in fullscreen. When explicitly set to
+// the fullscreen button will be hidden or disabled on macOS.
+func (o *WindowOptions) Fullscreen(v bool) *WindowOptions {
+	return o.set("fullscreen", v)
+}
+
+// Fullscreenable sets fullscreenable - Whether the window can be put into fullscreen mode. On
+// macOS, also whether the maximize/zoom button should toggle full screen mode or maximize window.
+func (o *WindowOptions) Fullscreenable(v bool) *WindowOptions {
+	return o.set("fullscreenable", v)
+}
+
+// SkipTaskbar sets skipTaskbar - Whether to show the window in taskbar.
+func (o *WindowOptions) SkipTaskbar(v bool) *WindowOptions {
+	return o.set("skipTaskbar", v)
+}
+
+// Kiosk sets kiosk - The kiosk mode.
+func (o *WindowOptions) Kiosk(v bool) *WindowOptions {
+	return o.set("kiosk", v)
+}
+
+// Title sets title - Default window title.
+func (o *WindowOptions) Title(v string) *WindowOptions {
+	return o.set("title", v)
+}
+
+// Show sets show - Whether window should be shown when created.
+func (o *WindowOptions) Show(v bool) *WindowOptions {
+	return o.set("show", v)
+}
+
+// Frame sets frame
+func (o *WindowOptions) Frame(v bool) *WindowOptions {
+	return o.set("frame", v)
+}
+
+// Modal sets modal - Whether this is a modal window. This only works when the window is a child
+// window.
+func (o *WindowOptions) Modal(v bool) *WindowOptions {
+	return o.set("modal", v)
+}
+
+// AcceptFirstMouse sets acceptFirstMouse - Whether the web view accepts a single mouse-down event
+// that simultaneously activates the window.
+func (o *WindowOptions) AcceptFirstMouse(v bool) *WindowOptions {
+	return o.set("acceptFirstMouse", v)
+}
+
+// DisableAutoHideCursor sets disableAutoHideCursor - Whether to hide cursor when typing.
+func (o *WindowOptions) DisableAutoHideCursor(v bool) *WindowOptions {
+	return o.set("disableAutoHideCursor", v)
+}
+
+// AutoHideMenuBar sets autoHideMenuBar - Auto hide the menu bar unless the Alt key is pressed.
+func (o *WindowOptions) AutoHideMenuBar(v bool) *WindowOptions {
+	return o.set("autoHideMenuBar", v)
+}
+
+// EnableLargerThanScreen sets enableLargerThanScreen - Enable the window to be resized larger than
+// screen.
+func (o *WindowOptions) EnableLargerThanScreen(v bool) *WindowOptions {
+	return o.set("enableLargerThanScreen", v)
+}
+
+// HasShadow sets hasShadow - Whether window should have a shadow. This is only implemented on
+// macOS.
+func (o *WindowOptions) HasShadow(v bool) *WindowOptions {
+	return o.set("hasShadow", v)
+}
+
+// DarkTheme sets darkTheme - Forces using dark theme for the window, only works on some GTK+3
+// desktop environments.
+func (o *WindowOptions) DarkTheme(v bool) *WindowOptions {
+	return o.set("darkTheme", v)
+}
+
+// Transparent sets transparent
+func (o *WindowOptions) Transparent(v bool) *WindowOptions {
+	return o.set("transparent", v)
+}
+
+// Type sets type - The type of window, default is normal window, e.g.
+// desktop, dock, toolbar, splash or notification on Linux and desktop or textured on macOS.
+func (o *WindowOptions) Type(v string) *WindowOptions {
+	return o.set("type", v)
+}
+
+// ThickFrame sets thickFrame - Use WS_THICKFRAME style for frameless windows on Windows, which
+// adds standard window frame. Setting it to false will remove window shadow and window animations.
+func (o *WindowOptions) ThickFrame(v bool) *WindowOptions {
+	return o.set("thickFrame", v)
+}
+
+// ZoomToPageWidth sets zoomToPageWidth - Controls the behavior on macOS when option-clicking the
+// green stoplight button on the toolbar or by clicking the Window > Zoom menu item. This will also
+// affect the behavior when calling win.zoom() directly.
+func (o *WindowOptions) ZoomToPageWidth(v bool) *WindowOptions {
+	return o.set("zoomToPageWidth", v)
+}
+
+// DevTools sets devTools - Whether to enable DevTools.
+func (o *WindowOptions) DevTools(v bool) *WindowOptions {
+	return o.setWeb("devTools", v)
+}
+
+// NodeIntegration sets nodeIntegration - Whether node integration is enabled.
+func (o *WindowOptions) NodeIntegration(v bool) *WindowOptions {
+	return o.setWeb("nodeIntegration", v)
+}
+
+// Preload sets preload - Specifies a script that will be loaded before other scripts run in the
+// page. This script will always have access to node APIs no matter whether node integration is
+// turned on or off. The value should be the absolute file path to the script. When node integration
+// is turned off, the preload script can reintroduce Node global symbols back to the global scope.
+func (o *WindowOptions) Preload(v string) *WindowOptions {
+	return o.setWeb("preload", v)
+}
+
+// Partition sets partition - Sets the session used by the page according to the session's partition
+// string. If partition starts with persist:, the page will use a persistent session, if there is
+// no prefix the page will use an in-memory session. Default is the default session.
+func (o *WindowOptions) Partition(v string) *WindowOptions {
+	return o.setWeb("partition", v)
+}
+
+// JavaScript sets javascript - Enables JavaScript support.
+func (o *WindowOptions) JavaScript(v bool) *WindowOptions {
+	return o.setWeb("javascript", v)
+}
+
+// WebSecurity sets webSecurity
+func (o *WindowOptions) WebSecurity(v bool) *WindowOptions {
+	return o.setWeb("webSecurity", v)
+}
+
+// AllowRunningInsecureContent sets allowRunningInsecureContent - Allow an https page to run
+// JavaScript, CSS or plugins from http URLs.
+func (o *WindowOptions) AllowRunningInsecureContent(v bool) *WindowOptions {
+	return o.setWeb("allowRunningInsecureContent", v)
+}
+
+// Images sets images - Enables image support.
+func (o *WindowOptions) Images(v bool) *WindowOptions {
+	return o.setWeb("images", v)
+}
+
+// TextAreasAreResizable sets textAreasAreResizable - Make TextArea elements resizable.
+func (o *WindowOptions) TextAreasAreResizable(v bool) *WindowOptions {
+	return o.setWeb("textAreasAreResizable", v)
+}
+
+// WebGL sets webgl - Enables WebGL support.
+func (o *WindowOptions) WebGL(v bool) *WindowOptions {
+	return o.setWeb("webgl", v)
+}
+
+// WebAudio sets webaudio - Enables WebAudio support.
+func (o *WindowOptions) WebAudio(v bool) *WindowOptions {
+	return o.setWeb("webaudio", v)
+}
+
+// Plugins sets plugins - Whether plugins should be enabled.
+func (o *WindowOptions) Plugins(v bool) *WindowOptions {
+	return o.setWeb("plugins", v)
+}
+
+// ExperimentalFeatures sets experimentalFeatures - Enables Chromium's experimental features.
+func (o *WindowOptions) ExperimentalFeatures(v bool) *WindowOptions {
+	return o.setWeb("experimentalFeatures", v)
+}
+
+// ExperimentalCanvasFeatures sets experimentalCanvasFeatures - Enables Chromium's experimental
+// canvas features.
+func (o *WindowOptions) ExperimentalCanvasFeatures(v bool) *WindowOptions {
+	return o.setWeb("experimentalCanvasFeatures", v)
+}
+
+// ScrollBounce sets scrollBounce - Enables scroll bounce (rubber banding) effect on macOS.
+func (o *WindowOptions) ScrollBounce(v bool) *WindowOptions {
+	return o.setWeb("scrollBounce", v)
+}
+
+// BlinkFeatures sets blinkFeatures - The full list of supported feature strings can be found in the
+// file.
+func (o *WindowOptions) BlinkFeatures(v string) *WindowOptions {
+	return o.setWeb("blinkFeatures", v)
+}
+
+// DisableBlinkFeatures sets disableBlinkFeatures - The full list of supported feature strings can
+// be found in the RuntimeEnabledFeatures.in file.
+func (o *WindowOptions) DisableBlinkFeatures(v string) *WindowOptions {
+	return o.setWeb("disableBlinkFeatures", v)
+}
+
+// DefaultFontSize sets defaultFontSize
+func (o *WindowOptions) DefaultFontSize(v int) *WindowOptions {
+	return o.setWeb("defaultFontSize", v)
+}
+
+// DefaultMonospaceFontSize sets defaultMonospaceFontSize
+func (o *WindowOptions) DefaultMonospaceFontSize(v int) *WindowOptions {
+	return o.setWeb("defaultMonospaceFontSize", v)
+}
+
+// MinimumFontSize sets minimumFontSize
+func (o *WindowOptions) MinimumFontSize(v int) *WindowOptions {
+	return o.setWeb("minimumFontSize", v)
+}
+
+// DefaultEncoding sets defaultEncoding
+func (o *WindowOptions) DefaultEncoding(v string) *WindowOptions {
+	return o.setWeb("defaultEncoding", v)
+}
+
+// BackgroundThrottling sets backgroundThrottling - Whether to throttle animations and timers when
+// the page becomes background.
+func (o *WindowOptions) BackgroundThrottling(v bool) *WindowOptions {
+	return o.setWeb("backgroundThrottling", v)
+}
+
+// Offscreen sets offscreen - Whether to enable offscreen rendering for the browser window. See the
+// offscreen rendering tutorial for more details.
+func (o *WindowOptions) Offscreen(v bool) *WindowOptions {
+	return o.setWeb("offscreen", v)
+}
+
+// Sandbox sets sandbox - Whether to enable Chromium OS-level sandbox.
+func (o *WindowOptions) Sandbox(v bool) *WindowOptions {
+	return o.setWeb("sandbox", v)
+}
+
+// ContextIsolation sets contextIsolation - Whether to run Electron APIs and the specified script in
+// a separate JavaScript context. The Electron API will only be available in the script and not the
+// loaded page. This option should be used when loading potentially untrusted remote content to
+// ensure the loaded content cannot tamper with the script and any Electron APIs being used. You can
+// access this context in the dev tools by selecting the 'Electron Isolated Context' entry in the
+// combo box at the top of the Console tab. This option is currently experimental and may change or
+// be removed in future Electron releases.
+func (o *WindowOptions) ContextIsolation(v bool) *WindowOptions {
+	return o.setWeb("contextIsolation", v)
+}
+
+// AllowDisplayingInsecureContent sets allowDisplayingInsecureContent - Allow an https page to
+// display content like images from http URLs. Only in electron 1.4.
+func (o *WindowOptions) AllowDisplayingInsecureContent(v bool) *WindowOptions {
+	return o.setWeb("allowDisplayingInsecureContent", v)
+}
+
+var validVibrancy = map[BrowserWindowOptionsVibrancy]bool{}
+
+func init() {
+	for _, v := range []BrowserWindowOptionsVibrancy{
+		BrowserWindowOptionsVibrancyAppearanceBased,
+		BrowserWindowOptionsVibrancyLight,
+		BrowserWindowOptionsVibrancyDark,
+		BrowserWindowOptionsVibrancyTitlebar,
+		BrowserWindowOptionsVibrancySelection,
+		BrowserWindowOptionsVibrancyMenu,
+		BrowserWindowOptionsVibrancyPopover,
+		BrowserWindowOptionsVibrancySidebar,
+		BrowserWindowOptionsVibrancyMediumLight,
+		BrowserWindowOptionsVibrancyUltraDark,
+	} {
+		validVibrancy[v] = true
+	}
+}
+
+var windowColorRe = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{4}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+
+func (o *WindowOptions) intOpt(name string) (int, bool) {
+	v, ok := o.opts[name].(int)
+	return v, ok
+}
+
+func (o *WindowOptions) boolOpt(m map[string]interface{}, name string) (value, set bool) {
+	value, set = m[name].(bool)
+	return
+}
+
+// Validate reports conflicting or invalid options
+func (o *WindowOptions) Validate() error {
+	if modal, _ := o.boolOpt(o.opts, "modal"); modal && o.parent == nil {
+		return errors.New("electron: window options: modal needs a parent window")
+	}
+	_, hasX := o.opts["x"]
+	if center, _ := o.boolOpt(o.opts, "center"); center && hasX {
+		return errors.New("electron: window options: center conflicts with position")
+	}
+	for _, dim := range []string{"Width", "Height"} {
+		size, hasSize := o.intOpt(strings.ToLower(dim))
+		min, hasMin := o.intOpt("min" + dim)
+		max, hasMax := o.intOpt("max" + dim)
+		if hasSize && size <= 0 {
+			return fmt.Errorf("electron: window options: invalid %s %d", strings.ToLower(dim), size)
+		}
+		if hasMin && hasMax && min > max {
+			return fmt.Errorf("electron: window options: min%s %d greater than max%s %d", dim, min, dim, max)
+		}
+		if hasSize && hasMin && size < min {
+			return fmt.Errorf("electron: window options: %s %d smaller than min%s %d", strings.ToLower(dim), size, dim, min)
+		}
+		if hasSize && hasMax && size > max {
+			return fmt.Errorf("electron: window options: %s %d greater than max%s %d", strings.ToLower(dim), size, dim, max)
+		}
+	}
+	if c, ok := o.opts["backgroundColor"].(string); ok && !windowColorRe.MatchString(c) {
+		return fmt.Errorf("electron: window options: invalid backgroundColor %q", c)
+	}
+	if v, ok := o.opts["titleBarStyle"].(string); ok {
+		switch BrowserWindowOptionsTitleBarStyle(v) {
+		case BrowserWindowOptionsTitleBarStyleDefault, BrowserWindowOptionsTitleBarStyleHidden, BrowserWindowOptionsTitleBarStyleHiddenInset:
+		default:
+			return fmt.Errorf("electron: window options: invalid titleBarStyle %q", v)
+		}
+	}
+	if v, ok := o.opts["vibrancy"].(string); ok && !validVibrancy[BrowserWindowOptionsVibrancy(v)] {
+		return fmt.Errorf("electron: window options: invalid vibrancy %q", v)
+	}
+	if _, ok := o.web["partition"]; ok && o.session != nil {
+		return errors.New("electron: window options: session and partition are exclusive")
+	}
+	sandbox, _ := o.boolOpt(o.web, "sandbox")
+	if node, _ := o.boolOpt(o.web, "nodeIntegration"); sandbox && node {
+		return errors.New("electron: window options: sandbox disables nodeIntegration")
+	}
+	if z, ok := o.web["zoomFactor"].(float64); ok && z <= 0 {
+		return fmt.Errorf("electron: window options: invalid zoomFactor %v", z)
+	}
+	return nil
+}
+
+// Build validates o and returns the javascript options
+func (o *WindowOptions) Build() (*BrowserWindowBrowserWindowOptions, error) {
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	opt := &BrowserWindowBrowserWindowOptions{
+		Object: js.Global.Get("Object").New(),
+	}
+	for k, v := range o.opts {
+		opt.Set(k, v)
+	}
+	if o.parent != nil {
+		opt.Set("parent", o.parent.Object)
+	}
+	if o.icon != nil {
+		opt.Set("icon", o.icon.Object)
+	}
+	if len(o.web) > 0 || o.session != nil {
+		web := js.Global.Get("Object").New()
+		for k, v := range o.web {
+			web.Set(k, v)
+		}
+		if o.session != nil {
+			web.Set("session", o.session.Object)
+		}
+		opt.Set("webPreferences", web)
+	}
+	return opt, nil
+}
+
+// New validates o and creates the window
+func (o *WindowOptions) New() (*BrowserWindow, error) {
+	opt, err := o.Build()
+	if err != nil {
+		return nil, err
+	}
+	return NewBrowserWindow(opt), nil
+}
@@ -0,0 +1,167 @@
+package electron
+
+import "github.com/gopherjs/gopherjs/js"
+
+// WindowState is the persisted state of a window
+type WindowState struct {
+	X          int  `json:"x"`
+	Y          int  `json:"y"`
+	Width      int  `json:"width"`
+	Height     int  `json:"height"`
+	Positioned bool `json:"positioned"` // X and Y are meaningful
+	Maximized  bool `json:"maximized"`
+	FullScreen bool `json:"fullScreen"`
+}
+
+// WindowStateKeeper saves the bounds, maximized and full screen state of a
+// window into `<userData>/window-state-<name>.json` and restores them.
+type WindowStateKeeper struct {
+	State WindowState
+	// OnError is called when saving the state on `close` fails, the error is
+	// dropped when nil
+	OnError func(err error)
+	path    string
+}
+
+// minimum part of a window which must stay visible on a display
+const (
+	windowStateMinVisibleWidth  = 100
+	windowStateMinVisibleHeight = 50
+)
+
+// NewWindowStateEx loads the state saved under name, falling back to a
+// centered window of defWidth x defHeight. Saved bounds which are not
+// visible on any of the current displays, e.g. after a monitor was
+// unplugged, are dropped. It must be called after the app `ready` event.
+func NewWindowStateEx(name string, defWidth, defHeight int) *WindowStateKeeper {
+	k := &WindowStateKeeper{
+		State: WindowState{Width: defWidth, Height: defHeight},
+		path:  userDataPath("window-state-" + name + ".json"),
+	}
+	saved := WindowState{}
+	if err := readJSONFile(k.path, &saved); err == nil && saved.Width > 0 && saved.Height > 0 {
+		k.State = saved
+	}
+	k.validate()
+	return k
+}
+
+type windowStateRect struct {
+	x, y, width, height int
+}
+
+func rectFromJs(o *js.Object) windowStateRect {
+	return windowStateRect{
+		x:      o.Get("x").Int(),
+		y:      o.Get("y").Int(),
+		width:  o.Get("width").Int(),
+		height: o.Get("height").Int(),
+	}
+}
+
+func (r windowStateRect) intersection(o windowStateRect) (width, height int) {
+	min := func(a, b int) int {
+		if a < b {
+			return a
+		}
+		return b
+	}
+	max := func(a, b int) int {
+		if a > b {
+			return a
+		}
+		return b
+	}
+	width = min(r.x+r.width, o.x+o.width) - max(r.x, o.x)
+	height = min(r.y+r.height, o.y+o.height) - max(r.y, o.y)
+	return
+}
+
+// validate drops the position when the window would not be visible enough
+// on any display, and shrinks it to the primary display work area
+func (k *WindowStateKeeper) validate() {
+	screen := GetScreenModule()
+	s := &k.State
+	if s.Positioned {
+		win := windowStateRect{s.X, s.Y, s.Width, s.Height}
+		visible := false
+		displays := screen.GetAllDisplays()
+		for i := 0; i < displays.Length(); i++ {
+			w, h := win.intersection(rectFromJs(displays.Index(i).Get("workArea")))
+			if w >= windowStateMinVisibleWidth || (w > 0 && w >= s.Width) {
+				if h >= windowStateMinVisibleHeight || (h > 0 && h >= s.Height) {
+					visible = true
+					break
+				}
+			}
+		}
+		if !visible {
+			s.Positioned = false
+		}
+	}
+	area := rectFromJs(screen.GetPrimaryDisplay().Get("workArea"))
+	if !s.Positioned {
+		if s.Width > area.width {
+			s.Width = area.width
+		}
+		if s.Height > area.height {
+			s.Height = area.height
+		}
+	}
+}
+
+// Apply sets the saved size and position into the options of a window to
+// be created, the window is centered when there is no valid position.
+func (k *WindowStateKeeper) Apply(opt *BrowserWindowBrowserWindowOptions) {
+	opt.Width = int64(k.State.Width)
+	opt.Height = int64(k.State.Height)
+	if k.State.Positioned {
+		opt.X = int64(k.State.X)
+		opt.Y = int64(k.State.Y)
+	} else {
+		opt.Center = true
+	}
+}
+
+// Manage restores the maximized and full screen state of bw, then records
+// its state on `resize` and `move` and saves it on `close`.
+func (k *WindowStateKeeper) Manage(bw *BrowserWindow) {
+	if k.State.Maximized {
+		bw.Maximize()
+	}
+	if k.State.FullScreen {
+		bw.SetFullScreen(true)
+	}
+	update := func(args ...*js.Object) {
+		k.update(bw)
+	}
+	bw.On(EvtBrowserWindowResize, update)
+	bw.On(EvtBrowserWindowMove, update)
+	bw.On(EvtBrowserWindowClose, func(args ...*js.Object) {
+		k.update(bw)
+		if err := k.Save(); err != nil && k.OnError != nil {
+			k.OnError(err)
+		}
+	})
+}
+
+func (k *WindowStateKeeper) update(bw *BrowserWindow) {
+	if bw.IsDestroyed() {
+		return
+	}
+	s := &k.State
+	s.Maximized = bw.IsMaximized()
+	s.FullScreen = bw.IsFullScreen()
+	// keep the normal bounds to restore when leaving maximized or full screen
+	if s.Maximized || s.FullScreen || bw.IsMinimized() {
+		return
+	}
+	r := rectFromJs(bw.GetBounds())
+	s.X, s.Y, s.Width, s.Height = r.x, r.y, r.width, r.height
+	s.Positioned = true
+}
+
+// Save writes the state file
+func (k *WindowStateKeeper) Save() error {
+	return writeJSONFile(k.path, k.State)
+}
@@ -29,7 +29,6 @@ func openFileDialog() {
 }
 
 func main() {
-	electron.UseRemote()
 	js.Global.Set("openFileDialog", openFileDialog)
 	js.Global.Set("showMessage", func() {
 		d := electron.GetDialogModule()
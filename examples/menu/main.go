@@ -5,7 +5,6 @@ import (
 
 	"github.com/gopherjs/gopherjs/js"
 	electron "github.com/oskca/gopherjs-electron"
-	"github.com/oskca/gopherjs-electron/api/main/menu"
 	nodejs "github.com/oskca/gopherjs-nodejs"
 )
 
@@ -34,13 +33,13 @@ func main() {
 				SubMenu: subm,
 			},
 			{
-				Type: menu.TypeSeparator,
+				Type: electron.TypeSeparator,
 			},
 			{
-				Role: menu.RoleAbout,
+				Role: electron.RoleAbout,
 			},
 			{
-				Type: menu.TypeSeparator,
+				Type: electron.TypeSeparator,
 			},
 			{
 				Label: "sub options",
@@ -257,7 +257,7 @@ func (m *Method) defStaticMethodBody(w *Context, rawMethodName string) {
 	}
 	// body
 	fmt.Fprintf(w, "{\n")
-	fmt.Fprintf(w, "o := electron.Get(\"%s\")\n", w.base.Name)
+	fmt.Fprintf(w, "o := Get(\"%s\")\n", w.base.Name)
 	// ret
 	if m.Return != nil {
 		fmt.Fprintf(w, "ret := ")
@@ -287,7 +287,7 @@ func (m *Method) defConstructorBody(w *Context, rawMethodName string) {
 	fmt.Fprintf(w, " *%s", w.base.goSym())
 	// body
 	fmt.Fprintf(w, "{\n")
-	fmt.Fprintf(w, "o := electron.Get(\"%s\")\n", w.base.Name)
+	fmt.Fprintf(w, "o := Get(\"%s\")\n", w.base.Name)
 	fmt.Fprintf(w, "ret := ")
 	// parameters
 	fmt.Fprintf(w, "o.New(")
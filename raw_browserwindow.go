@@ -245,40 +245,40 @@ func WrapBrowserWindow(o *js.Object) *BrowserWindow {
 }
 
 func GetAllWindows() *js.Object {
-	o := electron.Get("BrowserWindow")
+	o := Get("BrowserWindow")
 	ret := o.Call("getAllWindows")
 	return ret
 }
 func GetFocusedWindow() *js.Object {
-	o := electron.Get("BrowserWindow")
+	o := Get("BrowserWindow")
 	ret := o.Call("getFocusedWindow")
 	return ret
 }
 func FromWebContents(WebContents *WebContents) *js.Object {
-	o := electron.Get("BrowserWindow")
+	o := Get("BrowserWindow")
 	ret := o.Call("fromWebContents", WebContents)
 	return ret
 }
 func FromId(Id int64) *js.Object {
-	o := electron.Get("BrowserWindow")
+	o := Get("BrowserWindow")
 	ret := o.Call("fromId", Id)
 	return ret
 }
 func AddDevToolsExtension(Path string) {
-	o := electron.Get("BrowserWindow")
+	o := Get("BrowserWindow")
 	o.Call("addDevToolsExtension", Path)
 }
 func RemoveDevToolsExtension(Name string) {
-	o := electron.Get("BrowserWindow")
+	o := Get("BrowserWindow")
 	o.Call("removeDevToolsExtension", Name)
 }
 func GetDevToolsExtensions() *js.Object {
-	o := electron.Get("BrowserWindow")
+	o := Get("BrowserWindow")
 	ret := o.Call("getDevToolsExtensions")
 	return ret
 }
 func NewBrowserWindow(Options *BrowserWindowBrowserWindowOptions) *BrowserWindow {
-	o := electron.Get("BrowserWindow")
+	o := Get("BrowserWindow")
 	ret := o.New(Options)
 	return WrapBrowserWindow(ret)
 }
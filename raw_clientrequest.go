@@ -47,7 +47,7 @@ func WrapClientRequest(o *js.Object) *ClientRequest {
 }
 
 func NewClientRequest(Options *ClientRequestClientRequestOptions) *ClientRequest {
-	o := electron.Get("ClientRequest")
+	o := Get("ClientRequest")
 	ret := o.New(Options)
 	return WrapClientRequest(ret)
 }
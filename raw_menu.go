@@ -24,25 +24,25 @@ func WrapMenu(o *js.Object) *Menu {
 }
 
 func SetApplicationMenu(Menu *Menu) {
-	o := electron.Get("Menu")
+	o := Get("Menu")
 	o.Call("setApplicationMenu", Menu)
 }
 func GetApplicationMenu() *js.Object {
-	o := electron.Get("Menu")
+	o := Get("Menu")
 	ret := o.Call("getApplicationMenu")
 	return ret
 }
 func SendActionToFirstResponder(Action string) {
-	o := electron.Get("Menu")
+	o := Get("Menu")
 	o.Call("sendActionToFirstResponder", Action)
 }
 func BuildFromTemplate(Template *js.Object) *js.Object {
-	o := electron.Get("Menu")
+	o := Get("Menu")
 	ret := o.Call("buildFromTemplate", Template)
 	return ret
 }
 func NewMenu() *Menu {
-	o := electron.Get("Menu")
+	o := Get("Menu")
 	ret := o.New()
 	return WrapMenu(ret)
 }
@@ -26,7 +26,7 @@ func WrapMenuItem(o *js.Object) *MenuItem {
 }
 
 func NewMenuItem(Options *MenuItemMenuItemOptions) *MenuItem {
-	o := electron.Get("MenuItem")
+	o := Get("MenuItem")
 	ret := o.New(Options)
 	return WrapMenuItem(ret)
 }
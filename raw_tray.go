@@ -67,7 +67,7 @@ func WrapTray(o *js.Object) *Tray {
 }
 
 func NewTray(Image *NativeImage) *Tray {
-	o := electron.Get("Tray")
+	o := Get("Tray")
 	ret := o.New(Image)
 	return WrapTray(ret)
 }